@@ -0,0 +1,95 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BindableKindsName is the name of the BindableKinds resource maintained by the operator.
+const BindableKindsName = "bindable-kinds"
+
+// BindableKindBinding describes a value collected when binding a service.
+type BindableKindBinding struct {
+	// Name is the name the value is exposed as in the binding, before any prefix is applied; for
+	// maps read from a Secret or ConfigMap, the keys of the referenced resource are exposed instead.
+	Name string `json:"name"`
+
+	// Path is the location of the value in the service resource.
+	Path string `json:"path"`
+
+	// ElementType is the type of the value found at path: string, map, sliceOfMaps or
+	// sliceOfStrings.
+	ElementType string `json:"elementType"`
+
+	// ObjectType indicates whether the value is read from the service resource itself (string) or
+	// from a Secret or ConfigMap referenced by it.
+	ObjectType string `json:"objectType"`
+
+	// BindAs is the delivery mode of the value, either env or volumemount; when empty the delivery
+	// mode configured in the Service Binding applies.
+	// +optional
+	BindAs string `json:"bindAs,omitempty"`
+
+	// Profile is the credential profile the value belongs to; when empty the value is collected
+	// regardless of the profile selected in the Service Binding.
+	// +optional
+	Profile string `json:"profile,omitempty"`
+}
+
+// BindableKind describes a kind of service providing binding metadata.
+type BindableKind struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+
+	// Bindings lists the values collected when binding services of this kind.
+	Bindings []BindableKindBinding `json:"bindings"`
+}
+
+// BindableKindsStatus defines the observed state of BindableKinds
+type BindableKindsStatus struct {
+	// Kinds lists the bindable kinds found in the cluster.
+	// +optional
+	Kinds []BindableKind `json:"kinds,omitempty"`
+}
+
+// BindableKinds lists the kinds of services in the cluster providing binding metadata, either
+// through CRD annotations, OLM descriptors or CRD schema extensions. It is maintained by the
+// operator.
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=bindablekinds,scope=Cluster
+// +kubebuilder:object:root=true
+type BindableKinds struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status BindableKindsStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BindableKindsList contains a list of BindableKinds
+type BindableKindsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BindableKinds `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BindableKinds{}, &BindableKindsList{})
+}
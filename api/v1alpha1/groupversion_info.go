@@ -32,6 +32,8 @@ var (
 
 	GroupVersionKind = GroupVersion.WithKind("ServiceBinding")
 
+	BindableKindsGroupVersionResource = GroupVersion.WithResource("bindablekinds")
+
 	// SchemeBuilder is used to add go types to the GroupVersionKind scheme
 	SchemeBuilder = &scheme.Builder{GroupVersion: GroupVersion}
 
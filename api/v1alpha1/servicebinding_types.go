@@ -39,6 +39,24 @@ const (
 	ApplicationNotFoundReason = "ApplicationNotFound"
 	// ServiceNotFoundReason is used when the service is not found.
 	ServiceNotFoundReason = "ServiceNotFound"
+	// RequiredBindingValueNotFoundReason is used when a value declared as required by a service's
+	// binding annotations is not found.
+	RequiredBindingValueNotFoundReason = "RequiredBindingValueNotFound"
+	// CredentialProfileNotFoundReason is used when the credential profile selected for a service
+	// hasn't been declared by it.
+	CredentialProfileNotFoundReason = "CredentialProfileNotFound"
+	// BindingProviderFailedReason is used when the binding provider of a service can't provide the
+	// binding values.
+	BindingProviderFailedReason = "BindingProviderFailed"
+	// KeyCollisionReason is used when binding keys provided by more than one source collide and
+	// the key collision policy doesn't resolve the collision.
+	KeyCollisionReason = "KeyCollision"
+	// MappingTemplateErrorReason is used when the template of a mapping can't be parsed, executed or
+	// refers to values that can't be found.
+	MappingTemplateErrorReason = "MappingTemplateError"
+	// BindingDefinitionFailedReason is used when a binding annotation of a service is invalid, or
+	// fails collecting its value.
+	BindingDefinitionFailedReason = "BindingDefinitionFailed"
 
 	BindingInjectedReason = "BindingInjected"
 )
@@ -80,14 +98,165 @@ type ServiceBindingSpec struct {
 	// See MountPath attribute description for more details.
 	// +optional
 	BindAsFiles bool `json:"bindAsFiles,omitempty"`
+
+	// DetectBindingFields enables the detection of well-known fields, such as host, port or
+	// password, in services without binding metadata. Propose only reports the detected fields in
+	// the status, while Apply also collects them.
+	// +optional
+	DetectBindingFields DetectionMode `json:"detectBindingFields,omitempty"`
+
+	// NamingStrategy defines how the names of the binding values collected from services are
+	// built: Default (prefixes and keys joined with "_" in upper case), NoPrefix, PreserveCase,
+	// Flat (the keys as they are, as mandated by the Service Binding specification) or a Go
+	// template such as "{{.Prefix}}_{{.Key}}". Custom mappings are named as declared.
+	// +optional
+	NamingStrategy string `json:"namingStrategy,omitempty"`
+
+	// KeyCollisionPolicy defines what happens when the same binding key is provided by more than
+	// one service or mapping: Fail, FirstWins, LastWins (the default, letting mappings override
+	// the values collected from services) or Suffix, appending the id of each service, or its
+	// name, to the colliding keys. Collisions are reported in the status whatever the policy.
+	// +optional
+	KeyCollisionPolicy KeyCollisionPolicy `json:"keyCollisionPolicy,omitempty"`
+
+	// BindingFiles requests additional entries in the binding secret, aggregating all the binding
+	// values in a single file of the given format; those are always delivered as files, alongside
+	// the file of each binding value.
+	// +optional
+	BindingFiles []BindingFile `json:"bindingFiles,omitempty"`
+
+	// Flavors adds entries following the conventions of application frameworks, translated from
+	// the values collected from each service: SpringBoot (e.g. spring.datasource.url), Quarkus
+	// (e.g. quarkus.datasource.jdbc.url) or Node (e.g. DATABASE_URL). Entries are named as
+	// properties when binding as files, and as the equivalent environment variables otherwise.
+	// +optional
+	Flavors []string `json:"flavors,omitempty"`
+
+	// ArrayFormat defines how the arrays found in the binding values collected from services are
+	// rendered: Indexed (the default, a value per element named after its index, e.g. HOSTS_0),
+	// Join (a single value joining the elements with ArraySeparator) or JSON (a single value
+	// holding the JSON encoding of the array). Binding annotations can override it through the
+	// arrayFormat key.
+	// +optional
+	ArrayFormat ArrayFormat `json:"arrayFormat,omitempty"`
+
+	// ArraySeparator joins the elements of arrays rendered with the Join format; it defaults to ",".
+	// +optional
+	ArraySeparator string `json:"arraySeparator,omitempty"`
+
+	// FileNaming defines how the binding values collected from services are named when delivered
+	// as files: Default (named as environment variables, following NamingStrategy) or Original
+	// (the keys as declared by the service, without prefixes and keeping their case, nested maps
+	// becoming subdirectories, e.g. dbCredentials/user). Environment variables aren't affected.
+	// +optional
+	FileNaming FileNaming `json:"fileNaming,omitempty"`
 }
 
+// BindingFile defines a file aggregating all the binding values
+type BindingFile struct {
+	// Format is the format of the file: Env (the binding values as environment variable
+	// assignments), JSON or YAML (the values collected from services nested under their prefixes)
+	// or Properties (the same values, with keys joined with ".")
+	Format BindingFileFormat `json:"format"`
+	// Name is the name of the file, and of its entry in the binding secret; it defaults to
+	// binding.env, binding.json, binding.yaml or binding.properties
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// BindingFileFormat is the format of a file aggregating all the binding values.
+// +kubebuilder:validation:Enum=Env;JSON;YAML;Properties
+type BindingFileFormat string
+
+const (
+	// BindingFileFormatEnv formats the binding values as a .env file.
+	BindingFileFormatEnv BindingFileFormat = "Env"
+	// BindingFileFormatJSON formats the binding values as a JSON document.
+	BindingFileFormatJSON BindingFileFormat = "JSON"
+	// BindingFileFormatYAML formats the binding values as a YAML document.
+	BindingFileFormatYAML BindingFileFormat = "YAML"
+	// BindingFileFormatProperties formats the binding values as a Java properties file.
+	BindingFileFormatProperties BindingFileFormat = "Properties"
+)
+
+// FileNaming is the way binding values delivered as files are named.
+// +kubebuilder:validation:Enum=Default;Original
+type FileNaming string
+
+const (
+	// FileNamingDefault names files as environment variables.
+	FileNamingDefault FileNaming = "Default"
+	// FileNamingOriginal names files after the original keys, nested maps becoming subdirectories.
+	FileNamingOriginal FileNaming = "Original"
+)
+
+// ArrayFormat is the way arrays are rendered into binding values.
+// +kubebuilder:validation:Enum=Indexed;Join;JSON
+type ArrayFormat string
+
+const (
+	// ArrayFormatIndexed renders each element of an array as its own value.
+	ArrayFormatIndexed ArrayFormat = "Indexed"
+	// ArrayFormatJoin renders an array as a single value joining its elements.
+	ArrayFormatJoin ArrayFormat = "Join"
+	// ArrayFormatJSON renders an array as a single value holding its JSON encoding.
+	ArrayFormatJSON ArrayFormat = "JSON"
+)
+
+// KeyCollisionPolicy configures how binding keys provided by more than one source are resolved.
+// +kubebuilder:validation:Enum=Fail;FirstWins;LastWins;Suffix
+type KeyCollisionPolicy string
+
+const (
+	// KeyCollisionPolicyFail fails the binding.
+	KeyCollisionPolicyFail KeyCollisionPolicy = "Fail"
+	// KeyCollisionPolicyFirstWins keeps the value of the first source, in the order services and
+	// mappings are declared.
+	KeyCollisionPolicyFirstWins KeyCollisionPolicy = "FirstWins"
+	// KeyCollisionPolicyLastWins keeps the value of the last source, in the order services and
+	// mappings are declared.
+	KeyCollisionPolicyLastWins KeyCollisionPolicy = "LastWins"
+	// KeyCollisionPolicySuffix keeps all the values, appending the id, or the name, of each
+	// service to its colliding keys; mappings keep their names.
+	KeyCollisionPolicySuffix KeyCollisionPolicy = "Suffix"
+)
+
+// DetectionMode configures what is done with the fields detected in services without binding
+// metadata.
+// +kubebuilder:validation:Enum=Propose;Apply
+type DetectionMode string
+
+const (
+	// DetectionModePropose reports the detected fields in the status without collecting them.
+	DetectionModePropose DetectionMode = "Propose"
+	// DetectionModeApply reports and collects the detected fields.
+	DetectionModeApply DetectionMode = "Apply"
+)
+
 // ServiceBindingMapping defines a new binding from set of existing bindings
 type Mapping struct {
 	// Name is the name of new binding
 	Name string `json:"name"`
 	// Value is a template which will be rendered and ibjected into the application
 	Value string `json:"value"`
+	// File delivers the mapping as a file at the root of the binding volume, whatever the
+	// delivery of the other binding values; Value is then the template of the whole file, e.g. a
+	// pgpass or a properties file
+	// +optional
+	File *MappingFile `json:"file,omitempty"`
+}
+
+// MappingFile defines the file a mapping is delivered as
+type MappingFile struct {
+	// Name is the name of the file; it defaults to the name of the mapping
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Mode is the mode of the file, e.g. 0600 (384 in decimal); it defaults to the mode of the
+	// binding volume
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=511
+	// +optional
+	Mode *int32 `json:"mode,omitempty"`
 }
 
 // ServiceBindingStatus defines the observed state of ServiceBinding
@@ -103,6 +272,88 @@ type ServiceBindingStatus struct {
 	Secret string `json:"secret"`
 	// Applications contain all the applications filtered by name or label
 	Applications []BoundApplication `json:"applications,omitempty"`
+	// InjectedEnvVars lists the environment variables referring to individual keys of the binding
+	// secret injected in the applications' containers, which are removed on unbinding
+	// +optional
+	InjectedEnvVars []string `json:"injectedEnvVars,omitempty"`
+	// InferredBindings lists the binding annotations detected for services without binding
+	// metadata, which can be added to the services to make the detection unnecessary
+	// +optional
+	InferredBindings []InferredBinding `json:"inferredBindings,omitempty"`
+	// KeyCollisions lists the binding keys provided by more than one service or mapping
+	// +optional
+	KeyCollisions []KeyCollision `json:"keyCollisions,omitempty"`
+	// SanitizedKeys lists the binding keys renamed, or dropped, because they were neither valid
+	// environment variable names nor, when delivered as files, valid Secret keys
+	// +optional
+	SanitizedKeys []SanitizedKey `json:"sanitizedKeys,omitempty"`
+	// MappingContext lists, for each service, the paths its resource can be referred by in the
+	// templates of mappings and the fields found in it
+	// +optional
+	MappingContext []MappingContextService `json:"mappingContext,omitempty"`
+}
+
+// MappingContextService describes the entries of the context of mapping templates built from a
+// service
+type MappingContextService struct {
+	metav1.GroupVersionKind `json:",inline"`
+	// Name is the name of the service
+	Name string `json:"name"`
+	// Paths are the template paths the resource of the service can be referred by, e.g.
+	// .v1alpha1.postgresql_baiju_dev.Database.db_demo, or .db when the service declares an id
+	Paths []string `json:"paths"`
+	// Fields lists the fields found in the resource of the service, relative to any of its paths;
+	// values are never included
+	// +optional
+	Fields []MappingContextField `json:"fields,omitempty"`
+	// Truncated is true when the resource has more fields than the ones listed
+	// +optional
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// MappingContextField describes a field available to mapping templates
+type MappingContextField struct {
+	// Path is the template path of the field, relative to the path of the service, e.g.
+	// .status.dbConfigMap
+	Path string `json:"path"`
+	// Type is the type of the value of the field: string, integer, number, boolean, map, list or
+	// null
+	Type string `json:"type"`
+}
+
+// SanitizedKey reports a binding key renamed, or dropped, because it wasn't valid
+type SanitizedKey struct {
+	// Key is the invalid binding key
+	Key string `json:"key"`
+	// Source describes the service or mapping providing the key
+	Source string `json:"source"`
+	// NewKey is the key the value is delivered as; it is empty when the key has been dropped
+	// +optional
+	NewKey string `json:"newKey,omitempty"`
+	// Reason describes why the key has been renamed or dropped
+	Reason string `json:"reason"`
+}
+
+// KeyCollision reports a binding key provided by more than one source
+type KeyCollision struct {
+	// Key is the colliding binding key
+	Key string `json:"key"`
+	// Sources describes the services and mappings providing the key, in order
+	Sources []string `json:"sources"`
+	// Resolution describes how the collision has been resolved, if it has
+	// +optional
+	Resolution string `json:"resolution,omitempty"`
+}
+
+// InferredBinding reports the binding annotations detected for a service
+type InferredBinding struct {
+	metav1.GroupVersionKind `json:",inline"`
+	// Namespace is the namespace of the service
+	Namespace string `json:"namespace"`
+	// Name is the name of the service
+	Name string `json:"name"`
+	// Annotations are the binding annotations equivalent to the detected fields
+	Annotations map[string]string `json:"annotations"`
 }
 
 // Service defines the selector based on resource name, version, and resource kind
@@ -114,6 +365,41 @@ type Service struct {
 	Namespace  *string `json:"namespace,omitempty"`
 	NamePrefix *string `json:"namePrefix,omitempty"`
 	Id         *string `json:"id,omitempty"`
+
+	// Profile selects the credential profile, declared by the service through
+	// service.binding.profile annotations, the binding values are collected from.
+	// +optional
+	Profile *string `json:"profile,omitempty"`
+
+	// NamingStrategy overrides the naming strategy of the Service Binding for the values
+	// collected from this service.
+	// +optional
+	NamingStrategy *string `json:"namingStrategy,omitempty"`
+
+	// Include lists glob patterns, such as "DATABASE_*", of the binding keys collected from this
+	// service to publish; all of them are published when empty. Patterns follow the syntax of Go's
+	// path.Match and are matched against the keys as named by the naming strategy.
+	// +optional
+	Include []string `json:"include,omitempty"`
+
+	// Exclude lists glob patterns of the binding keys collected from this service not to publish,
+	// even if included.
+	// +optional
+	Exclude []string `json:"exclude,omitempty"`
+
+	// Rename renames binding keys collected from this service, once filtered by Include and
+	// Exclude; keys not collected are ignored.
+	// +optional
+	Rename []KeyRename `json:"rename,omitempty"`
+}
+
+// KeyRename renames a binding key
+type KeyRename struct {
+	// From is the key as named by the naming strategy
+	From string `json:"from"`
+	// To is the key the value is published as
+	// +kubebuilder:validation:MinLength=1
+	To string `json:"to"`
 }
 
 // BoundApplication defines the application workloads to which the binding secret has
@@ -52,6 +52,136 @@ func (in *Application) DeepCopy() *Application {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BindableKind) DeepCopyInto(out *BindableKind) {
+	*out = *in
+	if in.Bindings != nil {
+		in, out := &in.Bindings, &out.Bindings
+		*out = make([]BindableKindBinding, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BindableKind.
+func (in *BindableKind) DeepCopy() *BindableKind {
+	if in == nil {
+		return nil
+	}
+	out := new(BindableKind)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BindableKindBinding) DeepCopyInto(out *BindableKindBinding) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BindableKindBinding.
+func (in *BindableKindBinding) DeepCopy() *BindableKindBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(BindableKindBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BindableKinds) DeepCopyInto(out *BindableKinds) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BindableKinds.
+func (in *BindableKinds) DeepCopy() *BindableKinds {
+	if in == nil {
+		return nil
+	}
+	out := new(BindableKinds)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BindableKinds) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BindableKindsList) DeepCopyInto(out *BindableKindsList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BindableKinds, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BindableKindsList.
+func (in *BindableKindsList) DeepCopy() *BindableKindsList {
+	if in == nil {
+		return nil
+	}
+	out := new(BindableKindsList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BindableKindsList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BindableKindsStatus) DeepCopyInto(out *BindableKindsStatus) {
+	*out = *in
+	if in.Kinds != nil {
+		in, out := &in.Kinds, &out.Kinds
+		*out = make([]BindableKind, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BindableKindsStatus.
+func (in *BindableKindsStatus) DeepCopy() *BindableKindsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BindableKindsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BindingFile) DeepCopyInto(out *BindingFile) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BindingFile.
+func (in *BindingFile) DeepCopy() *BindingFile {
+	if in == nil {
+		return nil
+	}
+	out := new(BindingFile)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BindingPath) DeepCopyInto(out *BindingPath) {
 	*out = *in
@@ -84,9 +214,72 @@ func (in *BoundApplication) DeepCopy() *BoundApplication {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InferredBinding) DeepCopyInto(out *InferredBinding) {
+	*out = *in
+	out.GroupVersionKind = in.GroupVersionKind
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InferredBinding.
+func (in *InferredBinding) DeepCopy() *InferredBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(InferredBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyCollision) DeepCopyInto(out *KeyCollision) {
+	*out = *in
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyCollision.
+func (in *KeyCollision) DeepCopy() *KeyCollision {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyCollision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyRename) DeepCopyInto(out *KeyRename) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyRename.
+func (in *KeyRename) DeepCopy() *KeyRename {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyRename)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Mapping) DeepCopyInto(out *Mapping) {
 	*out = *in
+	if in.File != nil {
+		in, out := &in.File, &out.File
+		*out = new(MappingFile)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Mapping.
@@ -99,6 +292,82 @@ func (in *Mapping) DeepCopy() *Mapping {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MappingContextField) DeepCopyInto(out *MappingContextField) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MappingContextField.
+func (in *MappingContextField) DeepCopy() *MappingContextField {
+	if in == nil {
+		return nil
+	}
+	out := new(MappingContextField)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MappingContextService) DeepCopyInto(out *MappingContextService) {
+	*out = *in
+	out.GroupVersionKind = in.GroupVersionKind
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]MappingContextField, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MappingContextService.
+func (in *MappingContextService) DeepCopy() *MappingContextService {
+	if in == nil {
+		return nil
+	}
+	out := new(MappingContextService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MappingFile) DeepCopyInto(out *MappingFile) {
+	*out = *in
+	if in.Mode != nil {
+		in, out := &in.Mode, &out.Mode
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MappingFile.
+func (in *MappingFile) DeepCopy() *MappingFile {
+	if in == nil {
+		return nil
+	}
+	out := new(MappingFile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SanitizedKey) DeepCopyInto(out *SanitizedKey) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SanitizedKey.
+func (in *SanitizedKey) DeepCopy() *SanitizedKey {
+	if in == nil {
+		return nil
+	}
+	out := new(SanitizedKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Service) DeepCopyInto(out *Service) {
 	*out = *in
@@ -119,6 +388,31 @@ func (in *Service) DeepCopyInto(out *Service) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Profile != nil {
+		in, out := &in.Profile, &out.Profile
+		*out = new(string)
+		**out = **in
+	}
+	if in.NamingStrategy != nil {
+		in, out := &in.NamingStrategy, &out.NamingStrategy
+		*out = new(string)
+		**out = **in
+	}
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rename != nil {
+		in, out := &in.Rename, &out.Rename
+		*out = make([]KeyRename, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Service.
@@ -196,7 +490,9 @@ func (in *ServiceBindingSpec) DeepCopyInto(out *ServiceBindingSpec) {
 	if in.Mappings != nil {
 		in, out := &in.Mappings, &out.Mappings
 		*out = make([]Mapping, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Services != nil {
 		in, out := &in.Services, &out.Services
@@ -215,6 +511,16 @@ func (in *ServiceBindingSpec) DeepCopyInto(out *ServiceBindingSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.BindingFiles != nil {
+		in, out := &in.BindingFiles, &out.BindingFiles
+		*out = make([]BindingFile, len(*in))
+		copy(*out, *in)
+	}
+	if in.Flavors != nil {
+		in, out := &in.Flavors, &out.Flavors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceBindingSpec.
@@ -242,6 +548,37 @@ func (in *ServiceBindingStatus) DeepCopyInto(out *ServiceBindingStatus) {
 		*out = make([]BoundApplication, len(*in))
 		copy(*out, *in)
 	}
+	if in.InjectedEnvVars != nil {
+		in, out := &in.InjectedEnvVars, &out.InjectedEnvVars
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InferredBindings != nil {
+		in, out := &in.InferredBindings, &out.InferredBindings
+		*out = make([]InferredBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.KeyCollisions != nil {
+		in, out := &in.KeyCollisions, &out.KeyCollisions
+		*out = make([]KeyCollision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SanitizedKeys != nil {
+		in, out := &in.SanitizedKeys, &out.SanitizedKeys
+		*out = make([]SanitizedKey, len(*in))
+		copy(*out, *in)
+	}
+	if in.MappingContext != nil {
+		in, out := &in.MappingContext, &out.MappingContext
+		*out = make([]MappingContextService, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceBindingStatus.
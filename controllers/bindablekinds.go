@@ -0,0 +1,246 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/redhat-developer/service-binding-operator/api/v1alpha1"
+	"github.com/redhat-developer/service-binding-operator/pkg/binding"
+	"github.com/redhat-developer/service-binding-operator/pkg/converter"
+	"github.com/redhat-developer/service-binding-operator/pkg/log"
+)
+
+const (
+	// bindableKindsResyncPeriod is the period the inventory is rebuilt regardless of CRD changes,
+	// picking up changes in CSV descriptors.
+	bindableKindsResyncPeriod = 5 * time.Minute
+	// bindableKindsRetryPeriod is the period to wait before retrying a failed inventory update.
+	bindableKindsRetryPeriod = 10 * time.Second
+)
+
+// BindableKindsUpdater maintains the cluster-scoped BindableKinds resource, listing the kinds of
+// services providing binding metadata; the inventory is rebuilt every time a CRD changes and
+// periodically.
+type BindableKindsUpdater struct {
+	client dynamic.Interface // kubernetes dynamic api client
+	logger *log.Log          // logger instance
+}
+
+// +kubebuilder:rbac:groups=operators.coreos.com,resources=bindablekinds,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=operators.coreos.com,resources=bindablekinds/status,verbs=get;update;patch
+
+// SetupWithManager registers the updater to be started by the Manager.
+func (u *BindableKindsUpdater) SetupWithManager(mgr ctrl.Manager) error {
+	client, err := dynamic.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	u.client = client
+	u.logger = log.NewLog("bindablekinds")
+	return mgr.Add(u)
+}
+
+// Start watches CRDs cluster wide, rebuilding the inventory until the stop channel is closed.
+func (u *BindableKindsUpdater) Start(stop <-chan struct{}) error {
+	// trigger is buffered so that notifications received while the inventory is being rebuilt
+	// are coalesced into a single update
+	trigger := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case trigger <- struct{}{}:
+		default:
+		}
+	}
+
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(u.client, bindableKindsResyncPeriod)
+	factory.ForResource(crdGVR).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { notify() },
+		UpdateFunc: func(interface{}, interface{}) { notify() },
+		DeleteFunc: func(interface{}) { notify() },
+	})
+	factory.Start(stop)
+	notify()
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-trigger:
+			if err := u.update(); err != nil {
+				u.logger.Error(err, "On updating bindable kinds")
+				time.AfterFunc(bindableKindsRetryPeriod, notify)
+			}
+		}
+	}
+}
+
+// update rebuilds the inventory and stores it in the BindableKinds resource.
+func (u *BindableKindsUpdater) update() error {
+	kinds, err := buildBindableKinds(u.logger, u.client)
+	if err != nil {
+		return err
+	}
+	return updateBindableKinds(u.client, kinds)
+}
+
+// buildBindableKinds inspects every served version of the CRDs in the cluster, returning the ones
+// providing binding metadata through CSV descriptors, CRD annotations or CRD schema extensions, or
+// having a binding profile.
+func buildBindableKinds(logger *log.Log, client dynamic.Interface) ([]v1alpha1.BindableKind, error) {
+	crds, err := client.Resource(crdGVR).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	o := newOLM(client, "")
+	ownedCRDs, err := o.ListCSVOwnedCRDs()
+	if err != nil {
+		return nil, err
+	}
+
+	kinds := make([]v1alpha1.BindableKind, 0)
+	for i := range crds.Items {
+		crd := &crds.Items[i]
+		group, _, err := unstructured.NestedString(crd.Object, "spec", "group")
+		if err != nil {
+			return nil, err
+		}
+		kind, _, err := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+		if err != nil {
+			return nil, err
+		}
+		versions, _, err := unstructured.NestedSlice(crd.Object, "spec", "versions")
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range versions {
+			version, ok := v.(map[string]interface{})
+			if !ok || version["served"] != true {
+				continue
+			}
+			// versions of malformed or partially populated CRDs are skipped
+			name, ok := version["name"].(string)
+			if !ok || len(name) == 0 {
+				continue
+			}
+			gvk := schema.GroupVersionKind{Group: group, Version: name, Kind: kind}
+			log := logger.WithValues("GVK", gvk)
+
+			// a missing CRDDescription only means no descriptors are available for this version
+			crdDescription, err := o.selectCRDDescription(gvk, crd, ownedCRDs)
+			if err != nil {
+				log.Trace("No CRDDescription found", "Error", err)
+			}
+			anns, err := collectCRDAnnotations(crd, gvk.Version, crdDescription)
+			if err != nil {
+				return nil, err
+			}
+
+			bindings := buildBindableKindBindings(log, withProfileAnnotations(anns, gvk))
+			if len(bindings) == 0 {
+				continue
+			}
+			kinds = append(kinds, v1alpha1.BindableKind{
+				Group:    gvk.Group,
+				Version:  gvk.Version,
+				Kind:     gvk.Kind,
+				Bindings: bindings,
+			})
+		}
+	}
+
+	sort.Slice(kinds, func(i, j int) bool {
+		a, b := kinds[i], kinds[j]
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Version < b.Version
+	})
+
+	return kinds, nil
+}
+
+// buildBindableKindBindings describes the values declared by the given binding annotations;
+// invalid annotations are skipped.
+func buildBindableKindBindings(logger *log.Log, anns map[string]string) []v1alpha1.BindableKindBinding {
+	keys := make([]string, 0, len(anns))
+	for k := range anns {
+		if binding.IsBindingAnnotation(k) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var bindings []v1alpha1.BindableKindBinding
+	for _, k := range keys {
+		d, err := binding.Describe(k, anns[k])
+		if err != nil {
+			logger.Debug("Skipping invalid binding annotation", "Annotation", k, "Error", err)
+			continue
+		}
+		bindings = append(bindings, v1alpha1.BindableKindBinding{
+			Name:        d.Name,
+			Path:        d.Path,
+			ElementType: d.ElementType,
+			ObjectType:  d.ObjectType,
+			BindAs:      string(d.BindAs),
+			Profile:     d.Profile,
+		})
+	}
+	return bindings
+}
+
+// updateBindableKinds stores the given inventory in the BindableKinds resource, creating it when
+// it doesn't exist yet.
+func updateBindableKinds(client dynamic.Interface, kinds []v1alpha1.BindableKind) error {
+	resourceClient := client.Resource(v1alpha1.BindableKindsGroupVersionResource)
+
+	u, err := resourceClient.Get(context.TODO(), v1alpha1.BindableKindsName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		bk := &v1alpha1.BindableKinds{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: v1alpha1.GroupVersion.String(),
+				Kind:       "BindableKinds",
+			},
+			ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.BindableKindsName},
+		}
+		if u, err = converter.ToUnstructured(bk); err != nil {
+			return err
+		}
+		u, err = resourceClient.Create(context.TODO(), u, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return err
+	}
+
+	bk := &v1alpha1.BindableKinds{}
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, bk); err != nil {
+		return err
+	}
+	if len(bk.Status.Kinds) == len(kinds) && (len(kinds) == 0 || reflect.DeepEqual(bk.Status.Kinds, kinds)) {
+		return nil
+	}
+
+	bk.Status.Kinds = kinds
+	if u, err = converter.ToUnstructured(bk); err != nil {
+		return err
+	}
+	_, err = resourceClient.UpdateStatus(context.TODO(), u, metav1.UpdateOptions{})
+	return err
+}
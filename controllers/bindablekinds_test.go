@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/redhat-developer/service-binding-operator/api/v1alpha1"
+	"github.com/redhat-developer/service-binding-operator/pkg/converter"
+	"github.com/redhat-developer/service-binding-operator/pkg/log"
+	"github.com/redhat-developer/service-binding-operator/test/mocks"
+)
+
+func TestBuildBindableKinds(t *testing.T) {
+	logger := log.NewLog("testBuildBindableKinds")
+	ns := "bindable-kinds"
+
+	f := mocks.NewFake(t, ns)
+
+	csv := mocks.ClusterServiceVersionMock(ns, "cluster-service-version")
+	csv.Spec.CustomResourceDefinitions.Owned[0].StatusDescriptors = []olmv1alpha1.StatusDescriptor{{
+		Path: "dbCredentials",
+		XDescriptors: []string{
+			"urn:alm:descriptor:io.kubernetes:Secret",
+			"service.binding:credentials:elementType=map",
+		},
+	}}
+	u, err := converter.ToUnstructured(&csv)
+	require.NoError(t, err)
+	f.AddMockResource(u)
+
+	crd := f.AddMockedUnstructuredDatabaseCRD()
+	crd.SetAnnotations(map[string]string{
+		"service.binding/port":      "path={.status.port},bindAs=env",
+		"service.binding/host":      "path={.status.host",
+		"unrelated.annotation/name": "path={.status.name}",
+	})
+
+	kinds, err := buildBindableKinds(logger, f.FakeDynClient())
+	require.NoError(t, err)
+	require.Equal(t, []v1alpha1.BindableKind{{
+		Group:   mocks.CRDName,
+		Version: mocks.CRDVersion,
+		Kind:    mocks.CRDKind,
+		Bindings: []v1alpha1.BindableKindBinding{
+			{
+				Name:        "credentials",
+				Path:        "status.dbCredentials",
+				ElementType: "map",
+				ObjectType:  "Secret",
+			},
+			{
+				Name:        "port",
+				Path:        "status.port",
+				ElementType: "string",
+				ObjectType:  "string",
+				BindAs:      "env",
+			},
+		},
+	}}, kinds)
+}
+
+func TestBuildBindableKindsWithoutBindingMetadata(t *testing.T) {
+	logger := log.NewLog("testBuildBindableKindsWithoutBindingMetadata")
+
+	f := mocks.NewFake(t, "bindable-kinds")
+	crd := f.AddMockedUnstructuredDatabaseCRD()
+	crd.SetAnnotations(nil)
+
+	kinds, err := buildBindableKinds(logger, f.FakeDynClient())
+	require.NoError(t, err)
+	require.Empty(t, kinds)
+}
+
+func TestBuildBindableKindsWithMalformedVersions(t *testing.T) {
+	logger := log.NewLog("testBuildBindableKindsWithMalformedVersions")
+
+	f := mocks.NewFake(t, "bindable-kinds")
+	crd := f.AddMockedUnstructuredDatabaseCRD()
+	crd.SetAnnotations(map[string]string{"service.binding/port": "path={.status.port}"})
+	require.NoError(t, unstructured.SetNestedSlice(crd.Object, []interface{}{
+		map[string]interface{}{"served": true},
+		map[string]interface{}{"served": true, "name": int64(1)},
+		map[string]interface{}{"served": true, "name": mocks.CRDVersion},
+	}, "spec", "versions"))
+
+	kinds, err := buildBindableKinds(logger, f.FakeDynClient())
+	require.NoError(t, err)
+	require.Len(t, kinds, 1)
+	require.Equal(t, mocks.CRDVersion, kinds[0].Version)
+}
+
+func TestUpdateBindableKinds(t *testing.T) {
+	f := mocks.NewFake(t, "bindable-kinds")
+	client := f.FakeDynClient()
+
+	getStatus := func() v1alpha1.BindableKindsStatus {
+		u, err := client.Resource(v1alpha1.BindableKindsGroupVersionResource).
+			Get(context.TODO(), v1alpha1.BindableKindsName, metav1.GetOptions{})
+		require.NoError(t, err)
+		bk := &v1alpha1.BindableKinds{}
+		require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, bk))
+		return bk.Status
+	}
+
+	kinds := []v1alpha1.BindableKind{{
+		Group:   mocks.CRDName,
+		Version: mocks.CRDVersion,
+		Kind:    mocks.CRDKind,
+		Bindings: []v1alpha1.BindableKindBinding{{
+			Name:        "port",
+			Path:        "status.port",
+			ElementType: "string",
+			ObjectType:  "string",
+		}},
+	}}
+
+	t.Run("creates the resource when missing", func(t *testing.T) {
+		require.NoError(t, updateBindableKinds(client, kinds))
+		require.Equal(t, kinds, getStatus().Kinds)
+	})
+
+	t.Run("updates the existing resource", func(t *testing.T) {
+		kinds[0].Bindings[0].BindAs = "volumemount"
+		require.NoError(t, updateBindableKinds(client, kinds))
+		require.Equal(t, kinds, getStatus().Kinds)
+	})
+
+	t.Run("clears the inventory", func(t *testing.T) {
+		require.NoError(t, updateBindableKinds(client, nil))
+		require.Empty(t, getStatus().Kinds)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	err "errors"
 	"fmt"
 	"path"
+	"sort"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -21,6 +22,7 @@ import (
 	"k8s.io/client-go/dynamic"
 
 	"github.com/redhat-developer/service-binding-operator/api/v1alpha1"
+	"github.com/redhat-developer/service-binding-operator/pkg/binding"
 	"github.com/redhat-developer/service-binding-operator/pkg/log"
 )
 
@@ -39,6 +41,14 @@ type binder struct {
 	modifier    extraFieldsModifier // extra modifier for CRDs before updating
 	restMapper  meta.RESTMapper     // RESTMapper to convert GVR from GVK
 	logger      *log.Log            // logger instance
+	// bindingTypes contains the delivery medium declared for individual binding secret keys
+	bindingTypes map[string]binding.BindingType
+	// filePaths contains the path of the files holding binding secret keys not named after them
+	filePaths map[string]string
+	// fileModes contains the mode of the files holding binding secret keys declaring one
+	fileModes map[string]int32
+	envKeys   []string // binding secret keys delivered as environment variables
+	fileKeys  []string // binding secret keys delivered as files
 }
 
 var knativeServiceGVR = schema.GroupVersionResource{Group: "serving.knative.dev", Version: "v1", Resource: "services"}
@@ -113,6 +123,78 @@ func (b *binder) getApplicationByLabelSelector() (*unstructured.UnstructuredList
 	return objList, nil
 }
 
+// loadBindingKeys reads the binding secret and groups its keys by the medium they should be
+// delivered through; keys without a declared medium follow the Service Binding's configuration.
+func (b *binder) loadBindingKeys() error {
+	secretRes := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "secrets"}
+	existingSecret, err := b.dynClient.Resource(secretRes).Namespace(b.sbr.GetNamespace()).Get(context.TODO(), b.sbr.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	data, _, err := unstructured.NestedMap(existingSecret.Object, "data")
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	b.envKeys, b.fileKeys = nil, nil
+	for _, k := range keys {
+		t, ok := b.bindingTypes[k]
+		if !ok && b.sbr.Spec.BindAsFiles {
+			t = binding.TypeVolumeMount
+		}
+		if t == binding.TypeVolumeMount {
+			b.fileKeys = append(b.fileKeys, k)
+		} else {
+			b.envKeys = append(b.envKeys, k)
+		}
+	}
+	return nil
+}
+
+// bindsAsFiles returns true when at least part of the binding is delivered as files.
+func (b *binder) bindsAsFiles() bool {
+	return len(b.fileKeys) > 0 || (len(b.envKeys) == 0 && b.sbr.Spec.BindAsFiles)
+}
+
+// bindsAsEnvVars returns true when at least part of the binding is delivered as environment
+// variables.
+func (b *binder) bindsAsEnvVars() bool {
+	return len(b.envKeys) > 0 || (len(b.fileKeys) == 0 && !b.sbr.Spec.BindAsFiles)
+}
+
+// isMixedBinding returns true when the binding is delivered both as files and as environment
+// variables; in this case only the relevant keys are exposed through each medium.
+func (b *binder) isMixedBinding() bool {
+	return b.bindsAsFiles() && b.bindsAsEnvVars()
+}
+
+// buildVolumeItems returns the binding secret keys to be projected in the binding volume, or nil
+// in the case all of them should be, each in the file named after it with the volume's mode.
+func (b *binder) buildVolumeItems() []corev1.KeyToPath {
+	if !b.isMixedBinding() && len(b.filePaths) == 0 && len(b.fileModes) == 0 {
+		return nil
+	}
+	items := make([]corev1.KeyToPath, 0, len(b.fileKeys))
+	for _, k := range b.fileKeys {
+		p, ok := b.filePaths[k]
+		if !ok {
+			p = k
+		}
+		item := corev1.KeyToPath{Key: k, Path: p}
+		if mode, ok := b.fileModes[k]; ok {
+			item.Mode = &mode
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
 // extractSpecVolumes based on volume path, extract it unstructured. It can return error on trying
 // to find data in informed Unstructured object.
 func (b *binder) extractSpecVolumes(obj *unstructured.Unstructured) ([]interface{}, error) {
@@ -152,6 +234,9 @@ func (b *binder) removeSpecVolumes(
 	if err != nil {
 		return nil, err
 	}
+	if len(volumes) == 0 {
+		return obj, nil
+	}
 	volumes = b.removeVolumes(volumes)
 	if err = unstructured.SetNestedSlice(obj.Object, volumes, b.getVolumesPath()...); err != nil {
 		return nil, err
@@ -165,6 +250,8 @@ func (b *binder) updateVolumes(volumes []interface{}) ([]interface{}, error) {
 	name := b.sbr.GetName()
 	log := b.logger
 
+	items := b.buildVolumeItems()
+
 	log.Debug("Checking if binding volume is already defined...")
 	for _, v := range volumes {
 		volume, ok := v.(map[string]interface{})
@@ -173,6 +260,22 @@ func (b *binder) updateVolumes(volumes []interface{}) ([]interface{}, error) {
 		}
 		if name == volume["name"] {
 			log.Debug("Volume is already defined!")
+			// only the projected keys are updated, keeping fields defaulted by the API server
+			if len(items) == 0 {
+				unstructured.RemoveNestedField(volume, "secret", "items")
+				return volumes, nil
+			}
+			uItems := make([]interface{}, 0, len(items))
+			for _, item := range items {
+				uItem := map[string]interface{}{"key": item.Key, "path": item.Path}
+				if item.Mode != nil {
+					uItem["mode"] = int64(*item.Mode)
+				}
+				uItems = append(uItems, uItem)
+			}
+			if e := unstructured.SetNestedSlice(volume, uItems, "secret", "items"); e != nil {
+				return nil, e
+			}
 			return volumes, nil
 		}
 	}
@@ -183,6 +286,7 @@ func (b *binder) updateVolumes(volumes []interface{}) ([]interface{}, error) {
 		VolumeSource: corev1.VolumeSource{
 			Secret: &corev1.SecretVolumeSource{
 				SecretName: name,
+				Items:      items,
 			},
 		},
 	}
@@ -200,10 +304,10 @@ func (b *binder) removeVolumes(volumes []interface{}) []interface{} {
 	name := b.sbr.GetName()
 	var cleanVolumes []interface{}
 	for _, v := range volumes {
-		volume := v.(corev1.Volume)
-		if name != volume.Name {
-			cleanVolumes = append(cleanVolumes, v)
+		if volume, ok := v.(map[string]interface{}); ok && name == volume["name"] {
+			continue
 		}
+		cleanVolumes = append(cleanVolumes, v)
 	}
 	return cleanVolumes
 }
@@ -343,6 +447,56 @@ func (b *binder) appendEnvVar(
 	return updatedEnvList
 }
 
+// appendSecretKeyEnvVar append a single environment variable referring to the given key of the
+// binding secret onto informed "EnvVar" instance.
+func (b *binder) appendSecretKeyEnvVar(envList []corev1.EnvVar, key string) []corev1.EnvVar {
+	envVar := corev1.EnvVar{
+		Name: key,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: b.sbr.GetName()},
+				Key:                  key,
+			},
+		},
+	}
+
+	for i, env := range envList {
+		if env.Name == key {
+			envList[i] = envVar
+			return envList
+		}
+	}
+	return append(envList, envVar)
+}
+
+// injectedEnvVars returns the environment variables referring to individual keys of the binding
+// secret injected by the binder in the last update.
+func (b *binder) injectedEnvVars() []string {
+	if !b.isMixedBinding() {
+		return nil
+	}
+	return append([]string{}, b.envKeys...)
+}
+
+// removeSecretKeyEnvVars remove environment variables referring to keys of the binding secret,
+// as injected by the binder, but the ones named in keep; environment variables declared in the
+// application are left alone, even when referring to a Secret named after the Service Binding.
+func (b *binder) removeSecretKeyEnvVars(envList []corev1.EnvVar, keep map[string]bool) []corev1.EnvVar {
+	injected := make(map[string]bool, len(b.sbr.Status.InjectedEnvVars))
+	for _, n := range b.sbr.Status.InjectedEnvVars {
+		injected[n] = true
+	}
+	var cleanEnvList []corev1.EnvVar
+	for _, env := range envList {
+		if injected[env.Name] && !keep[env.Name] && env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil &&
+			env.ValueFrom.SecretKeyRef.Name == b.sbr.GetName() && env.ValueFrom.SecretKeyRef.Key == env.Name {
+			continue
+		}
+		cleanEnvList = append(cleanEnvList, env)
+	}
+	return cleanEnvList
+}
+
 // appendEnvFrom based on secret name and list of EnvFromSource instances, making sure secret is
 // part of the list or appended.
 func (b *binder) appendEnvFrom(envList []corev1.EnvFromSource, secret string) []corev1.EnvFromSource {
@@ -368,7 +522,7 @@ func (b *binder) appendEnvFrom(envList []corev1.EnvFromSource, secret string) []
 func (b *binder) removeEnvFrom(envList []corev1.EnvFromSource, secret string) []corev1.EnvFromSource {
 	var cleanEnvList []corev1.EnvFromSource
 	for _, env := range envList {
-		if env.SecretRef != nil && env.SecretRef.Name != secret {
+		if env.SecretRef == nil || env.SecretRef.Name != secret {
 			cleanEnvList = append(cleanEnvList, env)
 		}
 	}
@@ -394,12 +548,30 @@ func (b *binder) updateContainer(container interface{}) (map[string]interface{},
 		return nil, err
 	}
 
-	if !b.sbr.Spec.BindAsFiles {
+	// removing the entries injected by previous updates that the current delivery of the keys
+	// doesn't want anymore, e.g. the envFrom of a binding now delivered partly as files
+	envKeys := make(map[string]bool)
+	if b.isMixedBinding() {
+		for _, k := range b.envKeys {
+			envKeys[k] = true
+		}
+	}
+	c.Env = b.removeSecretKeyEnvVars(c.Env, envKeys)
+	if b.isMixedBinding() || !b.bindsAsEnvVars() {
+		c.EnvFrom = b.removeEnvFrom(c.EnvFrom, b.sbr.GetName())
+	}
+
+	if b.isMixedBinding() {
+		// only the keys meant to be environment variables are exposed as such
+		for _, k := range b.envKeys {
+			c.Env = b.appendSecretKeyEnvVar(c.Env, k)
+		}
+	} else if b.bindsAsEnvVars() {
 		c.EnvFrom = b.appendEnvFrom(c.EnvFrom, b.sbr.GetName())
 	}
 
 	// and adding volume mount entries
-	if b.sbr.Spec.BindAsFiles {
+	if b.bindsAsFiles() {
 
 		for _, e := range c.Env {
 			if e.Name == serviceBindingRootEnvVar {
@@ -435,15 +607,12 @@ func (b *binder) removeContainer(container interface{}) (map[string]interface{},
 		return nil, err
 	}
 
-	if !b.sbr.Spec.BindAsFiles {
-		// removing intermediary secret, effectively unbinding the application
-		c.EnvFrom = b.removeEnvFrom(c.EnvFrom, b.sbr.GetName())
-	}
-
-	if b.sbr.Spec.BindAsFiles {
-		// removing volume mount entries
-		c.VolumeMounts = b.removeVolumeMounts(c.VolumeMounts)
-	}
+	// removing intermediary secret, effectively unbinding the application, along with environment
+	// variables and volume mount entries, which might exist regardless of the Service Binding's
+	// current configuration in the case it changed, or individual keys declared their medium
+	c.EnvFrom = b.removeEnvFrom(c.EnvFrom, b.sbr.GetName())
+	c.Env = b.removeSecretKeyEnvVars(c.Env, nil)
+	c.VolumeMounts = b.removeVolumeMounts(c.VolumeMounts)
 
 	return runtime.DefaultUnstructuredConverter.ToUnstructured(c)
 }
@@ -542,6 +711,10 @@ func nestedMapComparison(a, b map[string]interface{}) *comparisonResult {
 func (b *binder) update(objs *unstructured.UnstructuredList) ([]*unstructured.Unstructured, error) {
 	updatedObjs := []*unstructured.Unstructured{}
 
+	if err := b.loadBindingKeys(); err != nil {
+		return nil, err
+	}
+
 	for _, obj := range objs.Items {
 		// modify the copy of the original object and use the original one later for comparison
 		updatedObj := obj.DeepCopy()
@@ -564,7 +737,7 @@ func (b *binder) update(objs *unstructured.UnstructuredList) ([]*unstructured.Un
 			}
 		}
 
-		if b.sbr.Spec.BindAsFiles {
+		if b.bindsAsFiles() {
 			if err = b.updateSpecVolumes(updatedObj); err != nil {
 				return nil, err
 			}
@@ -613,10 +786,8 @@ func (b *binder) remove(objs *unstructured.UnstructuredList) error {
 			return err
 		}
 
-		if b.sbr.Spec.BindAsFiles {
-			if updatedObj, err = b.removeSpecVolumes(updatedObj); err != nil {
-				return err
-			}
+		if updatedObj, err = b.removeSpecVolumes(updatedObj); err != nil {
+			return err
 		}
 
 		gk := updatedObj.GroupVersionKind().GroupKind()
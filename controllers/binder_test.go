@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/redhat-developer/service-binding-operator/api/v1alpha1"
+	"github.com/redhat-developer/service-binding-operator/pkg/binding"
 	"github.com/redhat-developer/service-binding-operator/test/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -190,8 +191,9 @@ func TestBinderNew(t *testing.T) {
 		require.Equal(t, configMapName, list[0].ConfigMapRef.Name)
 		require.Equal(t, secretName, list[1].SecretRef.Name)
 
-		list = binder.removeEnvFrom(envFrom, secretName)
-		require.Equal(t, 0, len(list))
+		list = binder.removeEnvFrom(list, secretName)
+		require.Equal(t, 1, len(list))
+		require.Equal(t, configMapName, list[0].ConfigMapRef.Name)
 	})
 
 	t.Run("appendEnv", func(t *testing.T) {
@@ -332,6 +334,313 @@ func TestBinderNew(t *testing.T) {
 	})
 }
 
+func TestBinderMixedBindingTypes(t *testing.T) {
+	ns := "binder"
+	name := "service-binding"
+	matchLabels := map[string]string{
+		"connects-to": "database",
+		"environment": "binder",
+	}
+
+	f := mocks.NewFake(t, ns)
+	sbr := f.AddMockedServiceBinding(name, nil, "ref", "", deploymentsGVR, matchLabels)
+	ensureDefaults(sbr.Spec.Application)
+	f.AddMockedUnstructuredDeployment("ref", matchLabels)
+	f.AddNamespacedMockedSecret(name, ns, map[string][]byte{
+		"DATABASE_HOST":   []byte("db.example.com"),
+		"DATABASE_CA_CRT": []byte("-----BEGIN CERTIFICATE-----"),
+	})
+
+	binder := newBinder(
+		context.TODO(),
+		f.FakeDynClient(),
+		sbr,
+		testutils.BuildTestRESTMapper(),
+	)
+	binder.bindingTypes = map[string]binding.BindingType{
+		"DATABASE_CA_CRT": binding.TypeVolumeMount,
+	}
+
+	list, err := binder.search()
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+
+	updatedObjects, err := binder.update(list)
+	require.NoError(t, err)
+	require.Len(t, updatedObjects, 1)
+
+	deployment := appsv1.Deployment{}
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(updatedObjects[0].Object, &deployment)
+	require.NoError(t, err)
+	podSpec := deployment.Spec.Template.Spec
+
+	t.Run("env keys are referenced individually", func(t *testing.T) {
+		c := podSpec.Containers[0]
+		require.Empty(t, c.EnvFrom)
+
+		envVar := getEnvVar(c.Env, "DATABASE_HOST")
+		require.NotNil(t, envVar)
+		require.NotNil(t, envVar.ValueFrom)
+		require.Equal(t, name, envVar.ValueFrom.SecretKeyRef.Name)
+		require.Equal(t, "DATABASE_HOST", envVar.ValueFrom.SecretKeyRef.Key)
+		require.Nil(t, getEnvVar(c.Env, "DATABASE_CA_CRT"))
+	})
+
+	t.Run("file keys are projected in the binding volume", func(t *testing.T) {
+		require.Len(t, podSpec.Volumes, 1)
+		require.Equal(t, []corev1.KeyToPath{{Key: "DATABASE_CA_CRT", Path: "DATABASE_CA_CRT"}},
+			podSpec.Volumes[0].Secret.Items)
+		require.Len(t, podSpec.Containers[0].VolumeMounts, 1)
+		require.Equal(t, "/bindings/"+name, podSpec.Containers[0].VolumeMounts[0].MountPath)
+	})
+
+	t.Run("injected env vars are recorded", func(t *testing.T) {
+		require.Equal(t, []string{"DATABASE_HOST"}, binder.injectedEnvVars())
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		sbr.Status.InjectedEnvVars = binder.injectedEnvVars()
+
+		// an environment variable declared in the application, referring to a Secret named
+		// after the Service Binding
+		declared := deployment.DeepCopy()
+		declaredEnvVar := corev1.EnvVar{
+			Name: "HOST",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: name},
+					Key:                  "DATABASE_HOST",
+				},
+			},
+		}
+		declared.Spec.Template.Spec.Containers[0].Env = append(declared.Spec.Template.Spec.Containers[0].Env, declaredEnvVar)
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(declared)
+		require.NoError(t, err)
+
+		updatedList := &unstructured.UnstructuredList{Items: []unstructured.Unstructured{{Object: obj}}}
+		err = binder.remove(updatedList)
+		require.NoError(t, err)
+
+		u, err := binder.dynClient.Resource(deploymentsGVR).Namespace(ns).
+			Get(context.TODO(), updatedObjects[0].GetName(), metav1.GetOptions{})
+		require.NoError(t, err)
+
+		removed := appsv1.Deployment{}
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &removed)
+		require.NoError(t, err)
+		require.Empty(t, removed.Spec.Template.Spec.Volumes)
+		c := removed.Spec.Template.Spec.Containers[0]
+		require.Nil(t, getEnvVar(c.Env, "DATABASE_HOST"))
+		require.Equal(t, &declaredEnvVar, getEnvVar(c.Env, "HOST"))
+		require.Empty(t, c.VolumeMounts)
+	})
+}
+
+func TestBinderDeliveryChanges(t *testing.T) {
+	ns := "binder"
+	name := "service-binding"
+	matchLabels := map[string]string{
+		"connects-to": "database",
+		"environment": "binder",
+	}
+
+	f := mocks.NewFake(t, ns)
+	sbr := f.AddMockedServiceBinding(name, nil, "ref", "", deploymentsGVR, matchLabels)
+	ensureDefaults(sbr.Spec.Application)
+	f.AddMockedUnstructuredDeployment("ref", matchLabels)
+	f.AddNamespacedMockedSecret(name, ns, map[string][]byte{
+		"DATABASE_HOST":   []byte("db.example.com"),
+		"DATABASE_CA_CRT": []byte("-----BEGIN CERTIFICATE-----"),
+	})
+
+	binder := newBinder(
+		context.TODO(),
+		f.FakeDynClient(),
+		sbr,
+		testutils.BuildTestRESTMapper(),
+	)
+
+	list, err := binder.search()
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+
+	// bind updates the application with the given binding types, recording the injected env vars
+	// as the reconciler does
+	bind := func(t *testing.T, bindingTypes map[string]binding.BindingType) corev1.Container {
+		binder.bindingTypes = bindingTypes
+		updatedObjects, err := binder.update(list)
+		require.NoError(t, err)
+		require.Len(t, updatedObjects, 1)
+		sbr.Status.InjectedEnvVars = binder.injectedEnvVars()
+		list = &unstructured.UnstructuredList{Items: []unstructured.Unstructured{*updatedObjects[0]}}
+
+		deployment := appsv1.Deployment{}
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(updatedObjects[0].Object, &deployment)
+		require.NoError(t, err)
+		return deployment.Spec.Template.Spec.Containers[0]
+	}
+
+	t.Run("env vars", func(t *testing.T) {
+		c := bind(t, nil)
+		require.Len(t, c.EnvFrom, 1)
+		require.Nil(t, getEnvVar(c.Env, "DATABASE_HOST"))
+		require.Empty(t, sbr.Status.InjectedEnvVars)
+	})
+
+	t.Run("env vars to mixed", func(t *testing.T) {
+		c := bind(t, map[string]binding.BindingType{"DATABASE_CA_CRT": binding.TypeVolumeMount})
+		require.Empty(t, c.EnvFrom)
+		require.NotNil(t, getEnvVar(c.Env, "DATABASE_HOST"))
+		require.Equal(t, []string{"DATABASE_HOST"}, sbr.Status.InjectedEnvVars)
+	})
+
+	t.Run("env var moved to a file", func(t *testing.T) {
+		c := bind(t, map[string]binding.BindingType{
+			"DATABASE_CA_CRT": binding.TypeVolumeMount,
+			"DATABASE_HOST":   binding.TypeVolumeMount,
+		})
+		require.Empty(t, c.EnvFrom)
+		require.Nil(t, getEnvVar(c.Env, "DATABASE_HOST"))
+		require.Empty(t, sbr.Status.InjectedEnvVars)
+	})
+
+	t.Run("mixed back to env vars", func(t *testing.T) {
+		bind(t, map[string]binding.BindingType{"DATABASE_CA_CRT": binding.TypeVolumeMount})
+		c := bind(t, nil)
+		require.Len(t, c.EnvFrom, 1)
+		require.Nil(t, getEnvVar(c.Env, "DATABASE_HOST"))
+		require.Empty(t, sbr.Status.InjectedEnvVars)
+	})
+
+	t.Run("remove once delivered as files", func(t *testing.T) {
+		sbr.Spec.BindAsFiles = true
+		defer func() { sbr.Spec.BindAsFiles = false }()
+		require.NoError(t, binder.remove(list))
+
+		u, err := binder.dynClient.Resource(deploymentsGVR).Namespace(ns).
+			Get(context.TODO(), list.Items[0].GetName(), metav1.GetOptions{})
+		require.NoError(t, err)
+		removed := appsv1.Deployment{}
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &removed)
+		require.NoError(t, err)
+		require.Empty(t, removed.Spec.Template.Spec.Containers[0].EnvFrom)
+	})
+}
+
+func TestBinderFilePaths(t *testing.T) {
+	ns := "binder"
+	name := "service-binding"
+	matchLabels := map[string]string{
+		"connects-to": "database",
+		"environment": "binder",
+	}
+
+	f := mocks.NewFake(t, ns)
+	sbr := f.AddMockedServiceBinding(name, nil, "ref", "", deploymentsGVR, matchLabels)
+	sbr.Spec.BindAsFiles = true
+	ensureDefaults(sbr.Spec.Application)
+	f.AddMockedUnstructuredDeployment("ref", matchLabels)
+	f.AddNamespacedMockedSecret(name, ns, map[string][]byte{
+		"host":               []byte("db.example.com"),
+		"dbCredentials.user": []byte("admin"),
+	})
+
+	binder := newBinder(
+		context.TODO(),
+		f.FakeDynClient(),
+		sbr,
+		testutils.BuildTestRESTMapper(),
+	)
+	binder.bindingTypes = map[string]binding.BindingType{
+		"host":               binding.TypeVolumeMount,
+		"dbCredentials.user": binding.TypeVolumeMount,
+	}
+	binder.filePaths = map[string]string{"dbCredentials.user": "dbCredentials/user"}
+
+	list, err := binder.search()
+	require.NoError(t, err)
+	updatedObjects, err := binder.update(list)
+	require.NoError(t, err)
+	require.Len(t, updatedObjects, 1)
+
+	deployment := appsv1.Deployment{}
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(updatedObjects[0].Object, &deployment)
+	require.NoError(t, err)
+	podSpec := deployment.Spec.Template.Spec
+
+	require.Len(t, podSpec.Volumes, 1)
+	require.Equal(t, []corev1.KeyToPath{
+		{Key: "dbCredentials.user", Path: "dbCredentials/user"},
+		{Key: "host", Path: "host"},
+	}, podSpec.Volumes[0].Secret.Items)
+	require.Empty(t, podSpec.Containers[0].EnvFrom)
+}
+
+func TestBinderFileModes(t *testing.T) {
+	ns := "binder"
+	name := "service-binding"
+	matchLabels := map[string]string{
+		"connects-to": "database",
+		"environment": "binder",
+	}
+
+	f := mocks.NewFake(t, ns)
+	sbr := f.AddMockedServiceBinding(name, nil, "ref", "", deploymentsGVR, matchLabels)
+	ensureDefaults(sbr.Spec.Application)
+	f.AddMockedUnstructuredDeployment("ref", matchLabels)
+	f.AddNamespacedMockedSecret(name, ns, map[string][]byte{
+		"DATABASE_HOST": []byte("db.example.com"),
+		"PGPASS":        []byte("db.example.com:5432:*:admin:secret\n"),
+	})
+
+	binder := newBinder(
+		context.TODO(),
+		f.FakeDynClient(),
+		sbr,
+		testutils.BuildTestRESTMapper(),
+	)
+	binder.bindingTypes = map[string]binding.BindingType{"PGPASS": binding.TypeVolumeMount}
+	binder.filePaths = map[string]string{"PGPASS": ".pgpass"}
+	binder.fileModes = map[string]int32{"PGPASS": 0600}
+
+	list, err := binder.search()
+	require.NoError(t, err)
+	updatedObjects, err := binder.update(list)
+	require.NoError(t, err)
+	require.Len(t, updatedObjects, 1)
+
+	deployment := appsv1.Deployment{}
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(updatedObjects[0].Object, &deployment)
+	require.NoError(t, err)
+	podSpec := deployment.Spec.Template.Spec
+
+	mode := int32(0600)
+	require.Len(t, podSpec.Volumes, 1)
+	require.Equal(t, []corev1.KeyToPath{
+		{Key: "PGPASS", Path: ".pgpass", Mode: &mode},
+	}, podSpec.Volumes[0].Secret.Items)
+
+	t.Run("rebinding an application having the volume", func(t *testing.T) {
+		binder.fileModes = map[string]int32{"PGPASS": 0400}
+		updatedObjects, err := binder.update(&unstructured.UnstructuredList{
+			Items: []unstructured.Unstructured{*updatedObjects[0]},
+		})
+		require.NoError(t, err)
+		require.Len(t, updatedObjects, 1)
+
+		deployment := appsv1.Deployment{}
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(updatedObjects[0].Object, &deployment)
+		require.NoError(t, err)
+		podSpec := deployment.Spec.Template.Spec
+
+		mode := int32(0400)
+		require.Len(t, podSpec.Volumes, 1)
+		require.Equal(t, []corev1.KeyToPath{
+			{Key: "PGPASS", Path: ".pgpass", Mode: &mode},
+		}, podSpec.Volumes[0].Secret.Items)
+	})
+}
+
 func TestBinderAppendEnvVar(t *testing.T) {
 	envName := "lastbound"
 	envList := []corev1.EnvVar{
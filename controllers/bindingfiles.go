@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/redhat-developer/service-binding-operator/api/v1alpha1"
+	"github.com/redhat-developer/service-binding-operator/pkg/binding"
+	"github.com/redhat-developer/service-binding-operator/pkg/envvars"
+)
+
+// defaultBindingFileNames are the names of the binding files not naming themselves.
+var defaultBindingFileNames = map[v1alpha1.BindingFileFormat]string{
+	v1alpha1.BindingFileFormatEnv:        "binding.env",
+	v1alpha1.BindingFileFormatJSON:       "binding.json",
+	v1alpha1.BindingFileFormatYAML:       "binding.yaml",
+	v1alpha1.BindingFileFormatProperties: "binding.properties",
+}
+
+// addBindingFiles adds the given files to the binding, aggregating its resolved values: Env files
+// are built from the binding entries, except the files rendered by mappings, while the other formats
+// are built from the entries' locations in the aggregated documents, nesting the values collected
+// from services under their prefixes. Files are always delivered as files.
+func addBindingFiles(b *internalBinding, files []v1alpha1.BindingFile) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	// the files rendered by mappings are configuration files, not binding values
+	envVars := make(map[string]string, len(b.envVars))
+	for k, v := range b.envVars {
+		if !b.fileMappings[k] {
+			envVars[k] = string(v)
+		}
+	}
+
+	doc := envvars.Document{}
+	names := make([]string, 0, len(b.docs))
+	for k := range b.docs {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		if err := doc.Add(b.docs[k].Value, b.docs[k].Path...); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range files {
+		name := f.Name
+		if len(name) == 0 {
+			name = defaultBindingFileNames[f.Format]
+		}
+
+		var content []byte
+		var err error
+		switch f.Format {
+		case v1alpha1.BindingFileFormatEnv:
+			// keys that can't be read by shells, e.g. the ones of values delivered as files, are
+			// left out by FormatEnv, and reported
+			var invalid []string
+			for k := range envVars {
+				if !envvars.IsValidEnvVarName(k) {
+					invalid = append(invalid, k)
+				}
+			}
+			sort.Strings(invalid)
+			for _, k := range invalid {
+				b.sanitizedKeys = append(b.sanitizedKeys, v1alpha1.SanitizedKey{
+					Key:    k,
+					Source: fmt.Sprintf("binding file %s", name),
+					Reason: "not a valid environment variable name",
+				})
+			}
+			content = envvars.FormatEnv(envVars)
+		case v1alpha1.BindingFileFormatJSON:
+			content, err = envvars.FormatJSON(doc)
+		case v1alpha1.BindingFileFormatYAML:
+			content, err = envvars.FormatYAML(doc)
+		case v1alpha1.BindingFileFormatProperties:
+			content, err = envvars.FormatProperties(doc)
+		default:
+			err = fmt.Errorf("unknown binding file format %q", f.Format)
+		}
+		if err != nil {
+			return err
+		}
+
+		if !envvars.IsValidSecretKey(name) {
+			return fmt.Errorf("binding file %q is not a valid Secret key", name)
+		}
+		if _, ok := b.envVars[name]; ok {
+			return fmt.Errorf("binding file %q collides with another binding entry", name)
+		}
+		b.envVars[name] = content
+		b.bindingTypes[name] = binding.TypeVolumeMount
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/redhat-developer/service-binding-operator/api/v1alpha1"
+	"github.com/redhat-developer/service-binding-operator/pkg/binding"
+	"github.com/redhat-developer/service-binding-operator/pkg/envvars"
+	"github.com/redhat-developer/service-binding-operator/test/mocks"
+)
+
+func TestProcessServiceContextsBindingFiles(t *testing.T) {
+	f := mocks.NewFake(t, "testing")
+	id := "db"
+	svcCtxs := serviceContextList{
+		{
+			service: mocks.UnstructuredDatabaseCRMock("testing", "db"),
+			id:      &id,
+			envVars: map[string]interface{}{
+				"host": "db.example.com",
+				"dbCredentials": map[string]interface{}{
+					"user": "admin",
+				},
+			},
+		},
+	}
+	mappings := []v1alpha1.Mapping{{Name: "URL", Value: "postgres://{{ .db.metadata.name }}"}}
+
+	t.Run("files are added", func(t *testing.T) {
+		b, err := NewRetriever(f.FakeDynClient()).ProcessServiceContexts(svcCtxs, &bindingOptions{
+			namePrefix: "app",
+			mappings:   mappings,
+			files: []v1alpha1.BindingFile{
+				{Format: v1alpha1.BindingFileFormatEnv},
+				{Format: v1alpha1.BindingFileFormatJSON, Name: "config.json"},
+				{Format: v1alpha1.BindingFileFormatYAML},
+				{Format: v1alpha1.BindingFileFormatProperties},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string][]byte{
+			"APP_DATABASE_HOST":               []byte("db.example.com"),
+			"APP_DATABASE_DBCREDENTIALS_USER": []byte("admin"),
+			"URL":                             []byte("postgres://db"),
+			"binding.env": []byte(`APP_DATABASE_DBCREDENTIALS_USER="admin"
+APP_DATABASE_HOST="db.example.com"
+URL="postgres://db"
+`),
+			"config.json": []byte(`{
+  "URL": "postgres://db",
+  "app": {
+    "Database": {
+      "dbCredentials": {
+        "user": "admin"
+      },
+      "host": "db.example.com"
+    }
+  }
+}
+`),
+			"binding.yaml": []byte(`URL: postgres://db
+app:
+  Database:
+    dbCredentials:
+      user: admin
+    host: db.example.com
+`),
+			"binding.properties": []byte(`URL=postgres\://db
+app.Database.dbCredentials.user=admin
+app.Database.host=db.example.com
+`),
+		}, b.envVars)
+		require.Equal(t, map[string]binding.BindingType{
+			"binding.env":        binding.TypeVolumeMount,
+			"config.json":        binding.TypeVolumeMount,
+			"binding.yaml":       binding.TypeVolumeMount,
+			"binding.properties": binding.TypeVolumeMount,
+		}, b.bindingTypes)
+	})
+
+	t.Run("files hold the published keys", func(t *testing.T) {
+		svcCtxs := serviceContextList{
+			{
+				service: mocks.UnstructuredDatabaseCRMock("testing", "db"),
+				id:      &id,
+				envVars: map[string]interface{}{
+					"host":     "db.example.com",
+					"port":     int64(5432),
+					"password": "secret",
+					"replicas": []interface{}{"db-0", "db-1"},
+				},
+				exclude: []string{"*_PASSWORD"},
+				rename:  []v1alpha1.KeyRename{{From: "DATABASE_HOST", To: "DB_HOST"}},
+			},
+		}
+		b, err := NewRetriever(f.FakeDynClient()).ProcessServiceContexts(svcCtxs, &bindingOptions{
+			arrays: envvars.ArrayRendering{Format: envvars.ArrayFormatJoin},
+			files: []v1alpha1.BindingFile{
+				{Format: v1alpha1.BindingFileFormatEnv},
+				{Format: v1alpha1.BindingFileFormatJSON},
+				{Format: v1alpha1.BindingFileFormatProperties},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, `DATABASE_PORT="5432"
+DATABASE_REPLICAS="db-0,db-1"
+DB_HOST="db.example.com"
+`, string(b.envVars["binding.env"]))
+		require.Equal(t, `{
+  "Database": {
+    "DB_HOST": "db.example.com",
+    "port": 5432,
+    "replicas": "db-0,db-1"
+  }
+}
+`, string(b.envVars["binding.json"]))
+		require.Equal(t, `Database.DB_HOST=db.example.com
+Database.port=5432
+Database.replicas=db-0,db-1
+`, string(b.envVars["binding.properties"]))
+	})
+
+	t.Run("files hold the resolved keys", func(t *testing.T) {
+		replica := "replica"
+		svcCtxs := append(serviceContextList{}, svcCtxs[0], &serviceContext{
+			service: mocks.UnstructuredDatabaseCRMock("testing", "replica"),
+			id:      &replica,
+			envVars: map[string]interface{}{"host": "replica.example.com"},
+		})
+		b, err := NewRetriever(f.FakeDynClient()).ProcessServiceContexts(svcCtxs, &bindingOptions{
+			collisionPolicy: v1alpha1.KeyCollisionPolicySuffix,
+			files:           []v1alpha1.BindingFile{{Format: v1alpha1.BindingFileFormatYAML}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, `Database:
+  dbCredentials:
+    user: admin
+  host_db: db.example.com
+  host_replica: replica.example.com
+`, string(b.envVars["binding.yaml"]))
+	})
+
+	t.Run("files rendered by mappings are left out", func(t *testing.T) {
+		b, err := NewRetriever(f.FakeDynClient()).ProcessServiceContexts(svcCtxs, &bindingOptions{
+			mappings: []v1alpha1.Mapping{
+				{Name: "GREETING", Value: "hello\nworld"},
+				{Name: "PGPASS", Value: "{{ .db.metadata.name }}:5432\n", File: &v1alpha1.MappingFile{Name: ".pgpass"}},
+			},
+			files: []v1alpha1.BindingFile{
+				{Format: v1alpha1.BindingFileFormatEnv},
+				{Format: v1alpha1.BindingFileFormatJSON},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "db:5432\n", string(b.envVars["PGPASS"]))
+		require.Equal(t, `DATABASE_DBCREDENTIALS_USER="admin"
+DATABASE_HOST="db.example.com"
+GREETING="hello\nworld"
+`, string(b.envVars["binding.env"]))
+		require.Equal(t, `{
+  "Database": {
+    "dbCredentials": {
+      "user": "admin"
+    },
+    "host": "db.example.com"
+  },
+  "GREETING": "hello\nworld"
+}
+`, string(b.envVars["binding.json"]))
+	})
+
+	t.Run("keys that aren't env var names are left out of env files", func(t *testing.T) {
+		b, err := NewRetriever(f.FakeDynClient()).ProcessServiceContexts(svcCtxs, &bindingOptions{
+			bindAsFiles: true,
+			mappings:    []v1alpha1.Mapping{{Name: "spring.datasource.url", Value: "jdbc:postgresql://{{ .db.metadata.name }}"}},
+			files: []v1alpha1.BindingFile{
+				{Format: v1alpha1.BindingFileFormatEnv},
+				{Format: v1alpha1.BindingFileFormatProperties},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, `DATABASE_DBCREDENTIALS_USER="admin"
+DATABASE_HOST="db.example.com"
+`, string(b.envVars["binding.env"]))
+		require.Contains(t, string(b.envVars["binding.properties"]), "spring.datasource.url=jdbc\\:postgresql\\://db\n")
+		require.Equal(t, []v1alpha1.SanitizedKey{
+			{Key: "spring.datasource.url", Source: "binding file binding.env", Reason: "not a valid environment variable name"},
+		}, b.sanitizedKeys)
+	})
+
+	t.Run("files colliding with binding entries", func(t *testing.T) {
+		_, err := NewRetriever(f.FakeDynClient()).ProcessServiceContexts(svcCtxs, &bindingOptions{
+			mappings: mappings,
+			files:    []v1alpha1.BindingFile{{Format: v1alpha1.BindingFileFormatEnv, Name: "URL"}},
+		})
+		require.EqualError(t, err, `binding file "URL" collides with another binding entry`)
+
+		_, err = NewRetriever(f.FakeDynClient()).ProcessServiceContexts(svcCtxs, &bindingOptions{
+			files: []v1alpha1.BindingFile{
+				{Format: v1alpha1.BindingFileFormatJSON},
+				{Format: v1alpha1.BindingFileFormatEnv, Name: "binding.json"},
+			},
+		})
+		require.EqualError(t, err, `binding file "binding.json" collides with another binding entry`)
+
+		_, err = NewRetriever(f.FakeDynClient()).ProcessServiceContexts(svcCtxs, &bindingOptions{
+			files: []v1alpha1.BindingFile{{Format: v1alpha1.BindingFileFormatEnv, Name: "conf/app.env"}},
+		})
+		require.EqualError(t, err, `binding file "conf/app.env" is not a valid Secret key`)
+	})
+}
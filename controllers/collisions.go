@@ -0,0 +1,371 @@
+package controllers
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/redhat-developer/service-binding-operator/api/v1alpha1"
+	"github.com/redhat-developer/service-binding-operator/pkg/binding"
+	"github.com/redhat-developer/service-binding-operator/pkg/envvars"
+)
+
+// errKeyCollision is returned when binding keys provided by more than one source can't be
+// resolved by the key collision policy.
+type errKeyCollision struct {
+	collisions []v1alpha1.KeyCollision
+}
+
+func (e errKeyCollision) Error() string {
+	msgs := make([]string, 0, len(e.collisions))
+	for _, c := range e.collisions {
+		msgs = append(msgs, fmt.Sprintf("%q provided by %s", c.Key, strings.Join(c.Sources, ", ")))
+	}
+	return fmt.Sprintf("binding key collision: %s", strings.Join(msgs, "; "))
+}
+
+// errSanitizedKeyCollision is returned when distinct binding keys are renamed into the same valid
+// name by the sanitization, which would drop all of their values but one.
+type errSanitizedKeyCollision struct {
+	name  string
+	first keySource
+	other keySource
+}
+
+func (e errSanitizedKeyCollision) Error() string {
+	return fmt.Sprintf("binding keys %q of %s and %q of %s are both sanitized into %q",
+		e.first.key, e.first.source, e.other.key, e.other.source, e.name)
+}
+
+// keySource is the value of a binding key provided by a service or a mapping.
+type keySource struct {
+	// source describes the service or mapping providing the value.
+	source string
+	// key is the key the value is provided under, before being sanitized.
+	key string
+	// suffix is appended to the key when collisions are resolved through suffixes; it is empty
+	// for mappings, which keep their names.
+	suffix      string
+	value       []byte
+	bindingType *binding.BindingType
+	// path is the location of the file holding the value, for values named after their original
+	// path or mappings naming their file; it is empty for the other values, whose files are named
+	// after their keys.
+	path string
+	// mode is the mode of the file holding the value, when declared.
+	mode *int32
+	// doc is the entry of the value in the aggregated binding files, if it is part of those.
+	doc *envvars.Leaf
+	// fileMapping is true for the values of mappings delivered as files of their own, which are
+	// configuration files rather than binding values.
+	fileMapping bool
+}
+
+// bindingKeys collects the values provided for each binding key, in the order the services and
+// mappings are declared. Keys are sanitized as they are added, into valid environment variable
+// names or, for keys delivered as files, valid Secret keys.
+type bindingKeys struct {
+	sources map[string][]keySource
+	// bindAsFiles is true when keys without a declared binding type are delivered as files.
+	bindAsFiles bool
+	// sanitized contains the keys renamed, or dropped, by the sanitization.
+	sanitized []v1alpha1.SanitizedKey
+	// origins contains the key, and its source, each valid name was first added for.
+	origins map[string]keySource
+}
+
+func newBindingKeys(bindAsFiles bool) *bindingKeys {
+	return &bindingKeys{
+		sources:     make(map[string][]keySource),
+		bindAsFiles: bindAsFiles,
+		origins:     make(map[string]keySource),
+	}
+}
+
+var invalidSuffixChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// describeService describes the service of the given context as a source of binding keys.
+func describeService(svcCtx *serviceContext) string {
+	gvk := svcCtx.service.GroupVersionKind()
+	return fmt.Sprintf("%s %s/%s", gvk.Kind, svcCtx.service.GetNamespace(), svcCtx.service.GetName())
+}
+
+// serviceSuffix returns the suffix of the keys provided by the service of the given context, when
+// collisions are resolved through suffixes: its id, or its name.
+func serviceSuffix(svcCtx *serviceContext) string {
+	return invalidSuffixChars.ReplaceAllString(stringValueOrDefault(svcCtx.id, svcCtx.service.GetName()), "_")
+}
+
+// addService adds the values collected from the given service, and their entries in the
+// aggregated binding files, if any.
+func (k *bindingKeys) addService(
+	svcCtx *serviceContext,
+	envVars map[string][]byte,
+	bindingTypes map[string]binding.BindingType,
+	docs map[string]envvars.Leaf,
+) error {
+	return k.add(describeService(svcCtx), serviceSuffix(svcCtx), envVars, bindingTypes, docs)
+}
+
+// add adds the values provided by the given source.
+func (k *bindingKeys) add(
+	source string,
+	suffix string,
+	envVars map[string][]byte,
+	bindingTypes map[string]binding.BindingType,
+	docs map[string]envvars.Leaf,
+) error {
+	keys := make([]string, 0, len(envVars))
+	for key := range envVars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := keySource{source: source, suffix: suffix, value: envVars[key]}
+		if t, ok := bindingTypes[key]; ok {
+			s.bindingType = &t
+		}
+		if doc, ok := docs[key]; ok {
+			s.doc = &doc
+		}
+		if err := k.addSource(key, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addSource adds the value of the given key, once sanitized; it fails when another key has been
+// sanitized into the same name, as the sanitization doesn't keep distinct keys apart.
+func (k *bindingKeys) addSource(key string, s keySource) error {
+	name, ok := k.sanitize(key, &s)
+	if !ok {
+		return nil
+	}
+	s.key = key
+	if origin, ok := k.origins[name]; !ok {
+		k.origins[name] = s
+	} else if origin.key != key {
+		return errSanitizedKeyCollision{name: name, first: origin, other: s}
+	}
+	k.sources[name] = append(k.sources[name], s)
+	return nil
+}
+
+// sanitize returns the valid name of the given key, recording the keys renamed or dropped; false
+// is returned when the key can't be used. Keys delivered as files holding a "/" are file paths,
+// whose sanitized path is set in s.
+func (k *bindingKeys) sanitize(key string, s *keySource) (string, bool) {
+	asFile := k.bindAsFiles
+	if s.bindingType != nil {
+		asFile = *s.bindingType == binding.TypeVolumeMount
+	}
+
+	var name, reason string
+	var err error
+	if asFile && strings.Contains(key, "/") {
+		var path string
+		if path, name, err = envvars.SanitizeFilePath(key); err == nil {
+			s.path = path
+			if path != key {
+				k.sanitized = append(k.sanitized, v1alpha1.SanitizedKey{Key: key, Source: s.source, NewKey: path, Reason: "not a valid file path"})
+			}
+			return name, true
+		}
+	} else if asFile {
+		name, err = envvars.SanitizeSecretKey(key)
+		reason = "not a valid Secret key"
+	} else {
+		name, err = envvars.SanitizeEnvVarName(key)
+		reason = "not a valid environment variable name"
+	}
+	if err != nil {
+		k.sanitized = append(k.sanitized, v1alpha1.SanitizedKey{Key: key, Source: s.source, Reason: err.Error()})
+		return "", false
+	}
+	if name != key {
+		k.sanitized = append(k.sanitized, v1alpha1.SanitizedKey{Key: key, Source: s.source, NewKey: name, Reason: reason})
+	}
+	return name, true
+}
+
+// addMappings adds the values of the given mappings, rendered in values; mappings declaring a
+// file are delivered as files, in the file named as declared.
+func (k *bindingKeys) addMappings(mappings []v1alpha1.Mapping, values map[string]interface{}) error {
+	added := make(map[string]bool)
+	for _, m := range mappings {
+		v, ok := values[m.Name]
+		if !ok || added[m.Name] {
+			continue
+		}
+		added[m.Name] = true
+		source := fmt.Sprintf("mapping %s", m.Name)
+		if m.File == nil {
+			docs := map[string]envvars.Leaf{m.Name: {Path: []string{m.Name}, Value: v}}
+			if err := k.add(source, "", map[string][]byte{m.Name: []byte(v.(string))}, nil, docs); err != nil {
+				return err
+			}
+			continue
+		}
+
+		bindingType := binding.TypeVolumeMount
+		s := keySource{
+			source:      source,
+			value:       []byte(v.(string)),
+			bindingType: &bindingType,
+			mode:        m.File.Mode,
+			fileMapping: true,
+		}
+		if len(m.File.Name) > 0 {
+			if !envvars.IsValidSecretKey(m.File.Name) {
+				return fmt.Errorf("file %q of mapping %q is not a valid file name", m.File.Name, m.Name)
+			}
+			s.path = m.File.Name
+		}
+		if err := k.addSource(m.Name, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// value returns the value collected under the given key; it fails when the key hasn't been
+// collected, or when more than one source provides it.
+func (k *bindingKeys) value(key string) ([]byte, error) {
+	sources := k.sources[key]
+	switch len(sources) {
+	case 0:
+		return nil, fmt.Errorf("binding key %q not found", key)
+	case 1:
+		return sources[0].value, nil
+	default:
+		names := make([]string, 0, len(sources))
+		for _, s := range sources {
+			names = append(names, s.source)
+		}
+		return nil, fmt.Errorf("binding key %q provided by %s", key, strings.Join(names, ", "))
+	}
+}
+
+// resolve returns the binding made of the collected values, resolving the keys provided by more
+// than one source according to the given policy.
+func (k *bindingKeys) resolve(policy v1alpha1.KeyCollisionPolicy) (*internalBinding, error) {
+	b := &internalBinding{
+		envVars:       make(map[string][]byte),
+		bindingTypes:  make(map[string]binding.BindingType),
+		filePaths:     make(map[string]string),
+		fileModes:     make(map[string]int32),
+		docs:          make(map[string]envvars.Leaf),
+		fileMappings:  make(map[string]bool),
+		sanitizedKeys: k.sanitized,
+	}
+	// set sets the value of s under name, either key or key suffixed; the path of its file and
+	// its location in the aggregated binding files, if any, are suffixed the same way
+	set := func(key string, name string, s keySource) {
+		b.envVars[name] = s.value
+		if s.bindingType != nil {
+			b.bindingTypes[name] = *s.bindingType
+		}
+		if len(s.path) > 0 {
+			b.filePaths[name] = s.path + strings.TrimPrefix(name, key)
+		}
+		if s.mode != nil {
+			b.fileModes[name] = *s.mode
+		}
+		if s.doc != nil {
+			p := append([]string{}, s.doc.Path...)
+			if name != key {
+				p[len(p)-1] += "_" + s.suffix
+			}
+			b.docs[name] = envvars.Leaf{Path: p, Value: s.doc.Value}
+		}
+		if s.fileMapping {
+			b.fileMappings[name] = true
+		}
+	}
+
+	keys := make([]string, 0, len(k.sources))
+	for key := range k.sources {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var unresolved []v1alpha1.KeyCollision
+	for _, key := range keys {
+		sources := k.sources[key]
+		if len(sources) == 1 {
+			set(key, key, sources[0])
+			continue
+		}
+
+		collision := v1alpha1.KeyCollision{Key: key}
+		for _, s := range sources {
+			collision.Sources = append(collision.Sources, s.source)
+		}
+
+		switch policy {
+		case v1alpha1.KeyCollisionPolicyFail:
+			unresolved = append(unresolved, collision)
+			continue
+		case v1alpha1.KeyCollisionPolicyFirstWins:
+			set(key, key, sources[0])
+			collision.Resolution = fmt.Sprintf("kept the value of %s", sources[0].source)
+		case v1alpha1.KeyCollisionPolicySuffix:
+			names, ok := k.suffixedNames(key, sources, b.envVars)
+			if !ok {
+				unresolved = append(unresolved, collision)
+				continue
+			}
+			for i, s := range sources {
+				set(key, names[i], s)
+			}
+			collision.Resolution = fmt.Sprintf("renamed to %s", strings.Join(names, ", "))
+		default:
+			last := sources[len(sources)-1]
+			set(key, key, last)
+			collision.Resolution = fmt.Sprintf("kept the value of %s", last.source)
+		}
+		b.collisions = append(b.collisions, collision)
+	}
+
+	if len(unresolved) > 0 {
+		return nil, errKeyCollision{collisions: append(b.collisions, unresolved...)}
+	}
+	return b, nil
+}
+
+// suffixedNames returns the names the given sources colliding on key are set under, suffixed
+// with the source's suffix in the case of the key; false is returned when those names collide
+// themselves, or with other keys.
+func (k *bindingKeys) suffixedNames(
+	key string,
+	sources []keySource,
+	envVars map[string][]byte,
+) ([]string, bool) {
+	names := make([]string, 0, len(sources))
+	seen := make(map[string]bool)
+	for _, s := range sources {
+		name := key
+		if len(s.suffix) > 0 {
+			suffix := s.suffix
+			if key == strings.ToUpper(key) {
+				suffix = strings.ToUpper(suffix)
+			}
+			name = key + "_" + suffix
+			if _, ok := k.sources[name]; ok {
+				return nil, false
+			}
+			if _, ok := envVars[name]; ok {
+				return nil, false
+			}
+		}
+		if seen[name] {
+			return nil, false
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names, true
+}
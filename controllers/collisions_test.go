@@ -0,0 +1,231 @@
+package controllers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/redhat-developer/service-binding-operator/api/v1alpha1"
+	"github.com/redhat-developer/service-binding-operator/pkg/binding"
+	"github.com/redhat-developer/service-binding-operator/test/mocks"
+)
+
+func TestProcessServiceContextsKeyCollisions(t *testing.T) {
+	f := mocks.NewFake(t, "testing")
+	primaryID := "primary"
+	replicaID := "replica"
+	emptyPrefix := ""
+	bindAsFile := binding.TypeVolumeMount
+
+	newServiceContexts := func() serviceContextList {
+		return serviceContextList{
+			{
+				service:      mocks.UnstructuredDatabaseCRMock("testing", "db-primary"),
+				id:           &primaryID,
+				namePrefix:   &emptyPrefix,
+				envVars:      map[string]interface{}{"host": "primary.example.com", "user": "admin"},
+				bindingTypes: map[string]binding.BindingType{"host": bindAsFile},
+			},
+			{
+				service:    mocks.UnstructuredDatabaseCRMock("testing", "db-replica"),
+				id:         &replicaID,
+				namePrefix: &emptyPrefix,
+				envVars:    map[string]interface{}{"host": "replica.example.com"},
+			},
+			{
+				service:    mocks.UnstructuredDatabaseCRMock("testing", "db-backup"),
+				namePrefix: &emptyPrefix,
+				envVars:    map[string]interface{}{"host": "backup.example.com"},
+			},
+		}
+	}
+	mappings := []v1alpha1.Mapping{{Name: "USER", Value: "{{ .primary.metadata.name }}"}}
+	hostSources := []string{
+		"Database testing/db-primary",
+		"Database testing/db-replica",
+		"Database testing/db-backup",
+	}
+	userCollision := v1alpha1.KeyCollision{
+		Key:        "USER",
+		Sources:    []string{"Database testing/db-primary", "mapping USER"},
+		Resolution: "kept the value of mapping USER",
+	}
+
+	testCases := []struct {
+		name                 string
+		policy               v1alpha1.KeyCollisionPolicy
+		expectedEnvVars      map[string][]byte
+		expectedBindingTypes map[string]binding.BindingType
+		expectedCollisions   []v1alpha1.KeyCollision
+	}{
+		{
+			name: "last wins by default",
+			expectedEnvVars: map[string][]byte{
+				"HOST": []byte("backup.example.com"),
+				"USER": []byte("db-primary"),
+			},
+			expectedBindingTypes: map[string]binding.BindingType{},
+			expectedCollisions: []v1alpha1.KeyCollision{
+				{Key: "HOST", Sources: hostSources, Resolution: "kept the value of Database testing/db-backup"},
+				userCollision,
+			},
+		},
+		{
+			name:   "first wins",
+			policy: v1alpha1.KeyCollisionPolicyFirstWins,
+			expectedEnvVars: map[string][]byte{
+				"HOST": []byte("primary.example.com"),
+				"USER": []byte("admin"),
+			},
+			expectedBindingTypes: map[string]binding.BindingType{"HOST": bindAsFile},
+			expectedCollisions: []v1alpha1.KeyCollision{
+				{Key: "HOST", Sources: hostSources, Resolution: "kept the value of Database testing/db-primary"},
+				{Key: "USER", Sources: userCollision.Sources, Resolution: "kept the value of Database testing/db-primary"},
+			},
+		},
+		{
+			name:   "suffix with service id",
+			policy: v1alpha1.KeyCollisionPolicySuffix,
+			expectedEnvVars: map[string][]byte{
+				"HOST_PRIMARY":   []byte("primary.example.com"),
+				"HOST_REPLICA":   []byte("replica.example.com"),
+				"HOST_DB_BACKUP": []byte("backup.example.com"),
+				"USER_PRIMARY":   []byte("admin"),
+				"USER":           []byte("db-primary"),
+			},
+			expectedBindingTypes: map[string]binding.BindingType{"HOST_PRIMARY": bindAsFile},
+			expectedCollisions: []v1alpha1.KeyCollision{
+				{Key: "HOST", Sources: hostSources, Resolution: "renamed to HOST_PRIMARY, HOST_REPLICA, HOST_DB_BACKUP"},
+				{Key: "USER", Sources: userCollision.Sources, Resolution: "renamed to USER_PRIMARY, USER"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := NewRetriever(f.FakeDynClient()).ProcessServiceContexts(
+				newServiceContexts(), &bindingOptions{collisionPolicy: tc.policy, mappings: mappings})
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedEnvVars, b.envVars)
+			require.Equal(t, tc.expectedBindingTypes, b.bindingTypes)
+			require.Equal(t, tc.expectedCollisions, b.collisions)
+		})
+	}
+
+	t.Run("fail", func(t *testing.T) {
+		_, err := NewRetriever(f.FakeDynClient()).ProcessServiceContexts(
+			newServiceContexts(), &bindingOptions{collisionPolicy: v1alpha1.KeyCollisionPolicyFail, mappings: mappings})
+		collisionErr := errKeyCollision{}
+		require.True(t, errors.As(err, &collisionErr))
+		require.Equal(t, []v1alpha1.KeyCollision{
+			{Key: "HOST", Sources: hostSources},
+			{Key: "USER", Sources: userCollision.Sources},
+		}, collisionErr.collisions)
+		require.EqualError(t, err, `binding key collision: "HOST" provided by Database testing/db-primary, `+
+			`Database testing/db-replica, Database testing/db-backup; "USER" provided by Database testing/db-primary, mapping USER`)
+	})
+
+	t.Run("suffixed keys colliding with other keys", func(t *testing.T) {
+		svcCtxs := newServiceContexts()
+		svcCtxs[2].envVars = map[string]interface{}{"host": "backup.example.com", "host_primary": "other"}
+		_, err := NewRetriever(f.FakeDynClient()).ProcessServiceContexts(
+			svcCtxs, &bindingOptions{collisionPolicy: v1alpha1.KeyCollisionPolicySuffix})
+		collisionErr := errKeyCollision{}
+		require.True(t, errors.As(err, &collisionErr))
+		require.Equal(t, []v1alpha1.KeyCollision{{Key: "HOST", Sources: hostSources}}, collisionErr.collisions)
+	})
+
+	t.Run("no collisions", func(t *testing.T) {
+		b, err := NewRetriever(f.FakeDynClient()).ProcessServiceContexts(
+			newServiceContexts()[:1], &bindingOptions{collisionPolicy: v1alpha1.KeyCollisionPolicyFail})
+		require.NoError(t, err)
+		require.Empty(t, b.collisions)
+	})
+}
+
+func TestProcessServiceContextsSanitizedKeys(t *testing.T) {
+	f := mocks.NewFake(t, "testing")
+	svcCtxs := serviceContextList{
+		{
+			service: mocks.UnstructuredDatabaseCRMock("testing", "db"),
+			envVars: map[string]interface{}{
+				"host":   "db.example.com",
+				"tls":    map[string]interface{}{"ca.crt": "certificate"},
+				"db-url": "postgres://db",
+			},
+			bindingTypes: map[string]binding.BindingType{"tls": binding.TypeVolumeMount},
+		},
+	}
+	mappings := []v1alpha1.Mapping{
+		{Name: "0_URL", Value: "postgres://db"},
+		{Name: "", Value: "dropped"},
+	}
+
+	b, err := NewRetriever(f.FakeDynClient()).ProcessServiceContexts(svcCtxs, &bindingOptions{
+		namingStrategy: "Flat",
+		mappings:       mappings,
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{
+		"host":       []byte("db.example.com"),
+		"tls_ca.crt": []byte("certificate"),
+		"db_url":     []byte("postgres://db"),
+		"_0_URL":     []byte("postgres://db"),
+	}, b.envVars)
+	require.Equal(t, map[string]binding.BindingType{"tls_ca.crt": binding.TypeVolumeMount}, b.bindingTypes)
+	require.ElementsMatch(t, []v1alpha1.SanitizedKey{
+		{Key: "db-url", Source: "Database testing/db", NewKey: "db_url", Reason: "not a valid environment variable name"},
+		{Key: "0_URL", Source: "mapping 0_URL", NewKey: "_0_URL", Reason: "not a valid environment variable name"},
+		{Key: "", Source: "mapping ", Reason: "empty key"},
+	}, b.sanitizedKeys)
+
+	t.Run("keys delivered as files", func(t *testing.T) {
+		b, err := NewRetriever(f.FakeDynClient()).ProcessServiceContexts(svcCtxs, &bindingOptions{
+			namingStrategy: "Flat",
+			bindAsFiles:    true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string][]byte{
+			"host":       []byte("db.example.com"),
+			"tls_ca.crt": []byte("certificate"),
+			"db-url":     []byte("postgres://db"),
+		}, b.envVars)
+		require.Empty(t, b.sanitizedKeys)
+	})
+
+	t.Run("sanitized keys collide", func(t *testing.T) {
+		svcCtx := *svcCtxs[0]
+		svcCtx.envVars = map[string]interface{}{"db-url": "a", "db_url": "b"}
+		_, err := NewRetriever(f.FakeDynClient()).ProcessServiceContexts(serviceContextList{&svcCtx}, &bindingOptions{
+			namingStrategy: "Flat",
+		})
+		require.EqualError(t, err, `binding keys "db-url" of Database testing/db and "db_url" of Database testing/db are both sanitized into "db_url"`)
+		require.True(t, errors.As(err, &errSanitizedKeyCollision{}))
+
+		replica := *svcCtxs[0]
+		replica.service = mocks.UnstructuredDatabaseCRMock("testing", "replica")
+		replica.envVars = map[string]interface{}{"db.url": "c"}
+		_, err = NewRetriever(f.FakeDynClient()).ProcessServiceContexts(serviceContextList{svcCtxs[0], &replica}, &bindingOptions{
+			namingStrategy:  "Flat",
+			collisionPolicy: v1alpha1.KeyCollisionPolicyLastWins,
+		})
+		require.EqualError(t, err, `binding keys "db-url" of Database testing/db and "db.url" of Database testing/replica are both sanitized into "db_url"`)
+	})
+
+	t.Run("same keys sanitized by several services", func(t *testing.T) {
+		replica := *svcCtxs[0]
+		replica.service = mocks.UnstructuredDatabaseCRMock("testing", "replica")
+		replica.envVars = map[string]interface{}{"db-url": "postgres://replica"}
+		b, err := NewRetriever(f.FakeDynClient()).ProcessServiceContexts(serviceContextList{svcCtxs[0], &replica}, &bindingOptions{
+			namingStrategy: "Flat",
+		})
+		require.NoError(t, err)
+		require.Equal(t, []byte("postgres://replica"), b.envVars["db_url"])
+		require.Equal(t, []v1alpha1.KeyCollision{{
+			Key:        "db_url",
+			Sources:    []string{"Database testing/db", "Database testing/replica"},
+			Resolution: "kept the value of Database testing/replica",
+		}}, b.collisions)
+	})
+}
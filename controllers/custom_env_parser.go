@@ -2,51 +2,307 @@ package controllers
 
 import (
 	"bytes"
-	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 	"text/template"
+	"text/template/parse"
 
 	"github.com/redhat-developer/service-binding-operator/api/v1alpha1"
+	"github.com/redhat-developer/service-binding-operator/pkg/templatefuncs"
 )
 
+// execErrorLocation extracts the failing expression and the cause from template execution errors.
+var execErrorLocation = regexp.MustCompile(`executing "[^"]*" at <(.*?)>: (.*)$`)
+
+// errMissingValue is returned by valueFunc when the value printed by an action can't be found.
+type errMissingValue struct {
+	path string
+}
+
+func (e errMissingValue) Error() string {
+	return fmt.Sprintf("%s can't be found", e.path)
+}
+
+// errMappingTemplate is returned when the template of a mapping can't be parsed, executed or
+// refers to values that can't be found.
+type errMappingTemplate struct {
+	// mapping is the name of the mapping.
+	mapping string
+	// path is the expression the template failed at, or the path that can't be found.
+	path string
+	// err is the cause of the failure; it is nil when path can't be found.
+	err error
+}
+
+func (e errMappingTemplate) Error() string {
+	switch {
+	case e.err == nil && len(e.path) > 0:
+		return fmt.Sprintf("mapping %q refers to %s, which can't be found", e.mapping, e.path)
+	case e.err == nil:
+		return fmt.Sprintf("mapping %q refers to values which can't be found", e.mapping)
+	case len(e.path) > 0:
+		return fmt.Sprintf("mapping %q failed evaluating %s: %v", e.mapping, e.path, e.err)
+	default:
+		return fmt.Sprintf("mapping %q: %v", e.mapping, e.err)
+	}
+}
+
+func (e errMappingTemplate) Unwrap() error {
+	return e.err
+}
+
+// bindingFunc is the template function returning the value of another mapping, or of a binding
+// key collected from the services.
+const bindingFunc = "binding"
+
+// valueFunc is the template function every action printing a value is piped to, failing when the
+// value can't be found instead of printing "<no value>"; values passed to other functions, such as
+// default, aren't checked.
+const valueFunc = "_mappingValue"
+
 // mappingsParser is responsible to interpolate a given EnvVar containing templates.
 type mappingsParser struct {
 	EnvMap []v1alpha1.Mapping
 	Cache  map[string]interface{}
+	// Keys are the binding keys collected from the services, which templates can refer to; nil
+	// when there are none.
+	Keys *bindingKeys
 }
 
 // newCustomEnvParser returns a new mappingsParser.
-func newMappingsParser(envMap []v1alpha1.Mapping, cache map[string]interface{}) *mappingsParser {
+func newMappingsParser(envMap []v1alpha1.Mapping, cache map[string]interface{}, keys *bindingKeys) *mappingsParser {
 	return &mappingsParser{
 		EnvMap: envMap,
 		Cache:  cache,
+		Keys:   keys,
 	}
 }
 
-// Parse interpolates and caches the templates in EnvMap.
+// Parse interpolates and caches the templates in EnvMap; templates referring to other mappings
+// through the binding function are interpolated after them.
 func (c *mappingsParser) Parse() (map[string]interface{}, error) {
 	data := make(map[string]interface{})
+	mappings := make(map[string]bool, len(c.EnvMap))
 	for _, v := range c.EnvMap {
-		tmpl, err := template.New("set").Funcs(template.FuncMap{"json": marshalToJSON}).Parse(v.Value)
+		mappings[v.Name] = true
+	}
+
+	funcs := templatefuncs.FuncMap()
+	funcs[bindingFunc] = func(name string) (string, error) {
+		if mappings[name] {
+			if v, ok := data[name]; ok {
+				return v.(string), nil
+			}
+			return "", fmt.Errorf("mapping %q not rendered yet; it has to be referred by a constant name", name)
+		}
+		if c.Keys == nil {
+			return "", fmt.Errorf("binding key %q not found", name)
+		}
+		v, err := c.Keys.value(name)
+		if err != nil {
+			return "", err
+		}
+		return string(v), nil
+	}
+	funcs[valueFunc] = func(path string, v ...interface{}) (interface{}, error) {
+		if len(v) == 0 || v[0] == nil {
+			return nil, errMissingValue{path: path}
+		}
+		return v[0], nil
+	}
+
+	templates := make([]*template.Template, len(c.EnvMap))
+	for i, v := range c.EnvMap {
+		tmpl, err := template.New("set").Funcs(funcs).Parse(v.Value)
 		if err != nil {
-			return data, err
+			return data, errMappingTemplate{mapping: v.Name, err: err}
 		}
+		for _, t := range tmpl.Templates() {
+			checkPrintedValues(t.Tree.Root)
+		}
+		templates[i] = tmpl
+	}
+
+	order, err := c.order(templates, mappings)
+	if err != nil {
+		return data, err
+	}
+
+	for _, i := range order {
+		v, tmpl := c.EnvMap[i], templates[i]
 
 		// evaluating template and storing value in a buffer
 		buf := new(bytes.Buffer)
 		err = tmpl.Execute(buf, c.Cache)
 		if err != nil {
-			return data, err
+			var missing errMissingValue
+			if errors.As(err, &missing) {
+				return data, errMappingTemplate{mapping: v.Name, path: missing.path}
+			}
+			if m := execErrorLocation.FindStringSubmatch(err.Error()); m != nil {
+				return data, errMappingTemplate{mapping: v.Name, path: m[1], err: errors.New(m[2])}
+			}
+			return data, errMappingTemplate{mapping: v.Name, err: err}
 		}
-
 		// saving buffer in cache
 		data[v.Name] = buf.String()
 	}
 	return data, nil
 }
-func marshalToJSON(m interface{}) (string, error) {
-	bytes, err := json.Marshal(m)
-	if err != nil {
-		return "", err
+
+// order returns the indexes of the mappings in the order they are interpolated: in declaration
+// order, but after the mappings they refer to; a cycle of references fails.
+func (c *mappingsParser) order(templates []*template.Template, mappings map[string]bool) ([]int, error) {
+	// the last mapping declared with a name provides its value, as it overwrites the previous ones
+	indexes := make(map[string]int, len(c.EnvMap))
+	for i, v := range c.EnvMap {
+		indexes[v.Name] = i
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(c.EnvMap))
+	order := make([]int, 0, len(c.EnvMap))
+	var path []string
+	var visit func(i int) error
+	visit = func(i int) error {
+		name := c.EnvMap[i].Name
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			cycle := path
+			for j, p := range path {
+				if p == name {
+					cycle = path[j:]
+					break
+				}
+			}
+			return errMappingTemplate{
+				mapping: name,
+				err:     fmt.Errorf("reference cycle %s", strings.Join(append(cycle, name), " -> ")),
+			}
+		}
+		state[i] = visiting
+		path = append(path, name)
+		for _, ref := range referredBindings(templates[i].Tree.Root) {
+			if !mappings[ref] {
+				continue
+			}
+			if err := visit(indexes[ref]); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = visited
+		order = append(order, i)
+		return nil
+	}
+	for i := range c.EnvMap {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// referredBindings returns the names passed as constants to the binding function in the given
+// template node.
+func referredBindings(node parse.Node) []string {
+	var names []string
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			names = append(names, referredBindings(child)...)
+		}
+	case *parse.ActionNode:
+		names = referredBindings(n.Pipe)
+	case *parse.IfNode:
+		names = referredBindings(&n.BranchNode)
+	case *parse.RangeNode:
+		names = referredBindings(&n.BranchNode)
+	case *parse.WithNode:
+		names = referredBindings(&n.BranchNode)
+	case *parse.BranchNode:
+		names = append(referredBindings(n.Pipe), referredBindings(n.List)...)
+		names = append(names, referredBindings(n.ElseList)...)
+	case *parse.TemplateNode:
+		names = referredBindings(n.Pipe)
+	case *parse.PipeNode:
+		if n == nil {
+			return nil
+		}
+		for _, cmd := range n.Cmds {
+			names = append(names, referredBindings(cmd)...)
+		}
+	case *parse.CommandNode:
+		if len(n.Args) > 1 {
+			ident, ok := n.Args[0].(*parse.IdentifierNode)
+			arg, isString := n.Args[1].(*parse.StringNode)
+			if ok && isString && ident.Ident == bindingFunc {
+				names = append(names, arg.Text)
+			}
+		}
+		for _, arg := range n.Args {
+			names = append(names, referredBindings(arg)...)
+		}
+	case *parse.ChainNode:
+		names = referredBindings(n.Node)
+	}
+	return names
+}
+
+// checkPrintedValues pipes the values printed by the actions of the given node to valueFunc,
+// passing the path they are referred by.
+func checkPrintedValues(node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			checkPrintedValues(child)
+		}
+	case *parse.ActionNode:
+		if len(n.Pipe.Decl) > 0 {
+			return
+		}
+		path := printedPath(n.Pipe)
+		n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      n.Pos,
+			Args: []parse.Node{
+				parse.NewIdentifier(valueFunc).SetPos(n.Pos),
+				&parse.StringNode{NodeType: parse.NodeString, Pos: n.Pos, Quoted: strconv.Quote(path), Text: path},
+			},
+		})
+	case *parse.IfNode:
+		checkPrintedValues(n.List)
+		checkPrintedValues(n.ElseList)
+	case *parse.RangeNode:
+		checkPrintedValues(n.List)
+		checkPrintedValues(n.ElseList)
+	case *parse.WithNode:
+		checkPrintedValues(n.List)
+		checkPrintedValues(n.ElseList)
+	}
+}
+
+// printedPath returns the path of the value printed by the given pipeline, i.e. the pipeline
+// itself; values looked up with index aren't named.
+func printedPath(pipe *parse.PipeNode) string {
+	last := pipe.Cmds[len(pipe.Cmds)-1]
+	if ident, ok := last.Args[0].(*parse.IdentifierNode); ok && ident.Ident == "index" {
+		return ""
 	}
-	return string(bytes), nil
+	return pipe.String()
 }
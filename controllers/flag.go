@@ -2,12 +2,22 @@ package controllers
 
 import (
 	"flag"
+	"time"
 )
 
 var (
 	maxConcurrentReconciles int
+
+	bindingProviderAllowedURLs string
+	bindingProviderTimeout     time.Duration
+	bindingProviderCacheTTL    time.Duration
+	bindingProviderCAFile      string
 )
 
 func RegisterFlags(flags *flag.FlagSet) {
 	flags.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 1, "max-concurrent-reconciles is the maximum number of concurrent Reconciles which can be run. Defaults to 1.")
+	flags.StringVar(&bindingProviderAllowedURLs, "binding-provider-allowed-urls", "", "binding-provider-allowed-urls is a comma separated list of URLs binding provider endpoints are allowed to be located under, sharing their scheme and host. Defaults to none, disabling binding providers.")
+	flags.DurationVar(&bindingProviderTimeout, "binding-provider-timeout", 10*time.Second, "binding-provider-timeout is the time limit of requests to binding providers. Defaults to 10s.")
+	flags.DurationVar(&bindingProviderCacheTTL, "binding-provider-cache-ttl", time.Minute, "binding-provider-cache-ttl is the period binding provider responses are reused for identical requests, unless the provider states otherwise. Defaults to 1m.")
+	flags.StringVar(&bindingProviderCAFile, "binding-provider-ca-file", "", "binding-provider-ca-file is the PEM file of the certificate authorities trusted for HTTPS binding providers. Defaults to the system certificate authorities.")
 }
@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/redhat-developer/service-binding-operator/pkg/binding"
+	"github.com/redhat-developer/service-binding-operator/pkg/log"
+)
+
+// matchAny returns true when key matches any of the given glob patterns.
+func matchAny(patterns []string, key string) (bool, error) {
+	for _, p := range patterns {
+		matched, err := path.Match(p, key)
+		if err != nil {
+			return false, fmt.Errorf("invalid key pattern %q: %v", p, err)
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// selectServiceKeys returns the entries collected from the given service context that are
+// published, together with their delivery medium: the ones matching its include patterns, if any,
+// and none of its exclude patterns, renamed as declared. The collected key each published entry
+// comes from is returned as well. Renaming the same key twice fails, while renames of keys that
+// aren't published are logged.
+func selectServiceKeys(
+	logger *log.Log,
+	svcCtx *serviceContext,
+	envVars map[string][]byte,
+	bindingTypes map[string]binding.BindingType,
+) (map[string][]byte, map[string]binding.BindingType, map[string]string, error) {
+	if len(svcCtx.include) == 0 && len(svcCtx.exclude) == 0 && len(svcCtx.rename) == 0 {
+		origins := make(map[string]string, len(envVars))
+		for k := range envVars {
+			origins[k] = k
+		}
+		return envVars, bindingTypes, origins, nil
+	}
+
+	selected := make(map[string][]byte, len(envVars))
+	selectedTypes := make(map[string]binding.BindingType)
+	for k, v := range envVars {
+		included := len(svcCtx.include) == 0
+		if !included {
+			var err error
+			if included, err = matchAny(svcCtx.include, k); err != nil {
+				return nil, nil, nil, fmt.Errorf("%s: %v", describeService(svcCtx), err)
+			}
+		}
+		excluded, err := matchAny(svcCtx.exclude, k)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%s: %v", describeService(svcCtx), err)
+		}
+		if !included || excluded {
+			continue
+		}
+		selected[k] = v
+		if t, ok := bindingTypes[k]; ok {
+			selectedTypes[k] = t
+		}
+	}
+
+	renamed := make(map[string][]byte, len(selected))
+	renamedTypes := make(map[string]binding.BindingType)
+	origins := make(map[string]string, len(selected))
+	sources := make(map[string]string)
+	renamedFrom := make(map[string]bool, len(svcCtx.rename))
+	for _, r := range svcCtx.rename {
+		if renamedFrom[r.From] {
+			return nil, nil, nil, fmt.Errorf("%s: key %q renamed more than once", describeService(svcCtx), r.From)
+		}
+		renamedFrom[r.From] = true
+		v, ok := selected[r.From]
+		if !ok {
+			logger.Warning("Renamed key isn't published by the service", "Service", describeService(svcCtx), "Key", r.From)
+			continue
+		}
+		if len(r.To) == 0 {
+			return nil, nil, nil, fmt.Errorf("%s: key %q renamed to an empty key", describeService(svcCtx), r.From)
+		}
+		if from, ok := sources[r.To]; ok {
+			return nil, nil, nil, fmt.Errorf("%s: keys %q and %q renamed to %q", describeService(svcCtx), from, r.From, r.To)
+		}
+		sources[r.To] = r.From
+		origins[r.To] = r.From
+		renamed[r.To] = v
+		if t, ok := selectedTypes[r.From]; ok {
+			renamedTypes[r.To] = t
+		}
+		delete(selected, r.From)
+	}
+	for k, v := range selected {
+		if from, ok := sources[k]; ok {
+			return nil, nil, nil, fmt.Errorf("%s: key %q renamed to %q, which is already collected", describeService(svcCtx), from, k)
+		}
+		origins[k] = k
+		renamed[k] = v
+		if t, ok := selectedTypes[k]; ok {
+			renamedTypes[k] = t
+		}
+	}
+	return renamed, renamedTypes, origins, nil
+}
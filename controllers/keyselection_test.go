@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/redhat-developer/service-binding-operator/api/v1alpha1"
+	"github.com/redhat-developer/service-binding-operator/pkg/binding"
+	"github.com/redhat-developer/service-binding-operator/pkg/log"
+	"github.com/redhat-developer/service-binding-operator/test/mocks"
+)
+
+func TestSelectServiceKeys(t *testing.T) {
+	envVars := map[string][]byte{
+		"DATABASE_DBCONNECTIONIP":   []byte("10.0.0.1"),
+		"DATABASE_DBCONNECTIONPORT": []byte("5432"),
+		"DATABASE_DBNAME":           []byte("orders"),
+		"DATABASE_IMAGE":            []byte("postgres"),
+		"DATABASE_TLS_CA_CRT":       []byte("-----BEGIN CERTIFICATE-----"),
+	}
+	bindingTypes := map[string]binding.BindingType{"DATABASE_TLS_CA_CRT": binding.TypeVolumeMount}
+
+	type testCase struct {
+		name                 string
+		include              []string
+		exclude              []string
+		rename               []v1alpha1.KeyRename
+		expected             map[string][]byte
+		expectedBindingTypes map[string]binding.BindingType
+		expectedErr          string
+	}
+
+	testCases := []testCase{
+		{
+			name:                 "all keys without selection",
+			expected:             envVars,
+			expectedBindingTypes: bindingTypes,
+		},
+		{
+			name:    "included keys",
+			include: []string{"DATABASE_DBCONNECTION*", "DATABASE_TLS_*"},
+			expected: map[string][]byte{
+				"DATABASE_DBCONNECTIONIP":   []byte("10.0.0.1"),
+				"DATABASE_DBCONNECTIONPORT": []byte("5432"),
+				"DATABASE_TLS_CA_CRT":       []byte("-----BEGIN CERTIFICATE-----"),
+			},
+			expectedBindingTypes: bindingTypes,
+		},
+		{
+			name:    "excluded keys",
+			exclude: []string{"DATABASE_IMAGE", "*_TLS_*"},
+			expected: map[string][]byte{
+				"DATABASE_DBCONNECTIONIP":   []byte("10.0.0.1"),
+				"DATABASE_DBCONNECTIONPORT": []byte("5432"),
+				"DATABASE_DBNAME":           []byte("orders"),
+			},
+			expectedBindingTypes: map[string]binding.BindingType{},
+		},
+		{
+			name:    "included, excluded and renamed keys",
+			include: []string{"DATABASE_DB*", "DATABASE_TLS_CA_CRT"},
+			exclude: []string{"DATABASE_DBNAME"},
+			rename: []v1alpha1.KeyRename{
+				{From: "DATABASE_DBCONNECTIONIP", To: "DB_HOST"},
+				{From: "DATABASE_DBCONNECTIONPORT", To: "DB_PORT"},
+				{From: "DATABASE_TLS_CA_CRT", To: "ca.crt"},
+				{From: "DATABASE_DBNAME", To: "DB_NAME"},
+			},
+			expected: map[string][]byte{
+				"DB_HOST": []byte("10.0.0.1"),
+				"DB_PORT": []byte("5432"),
+				"ca.crt":  []byte("-----BEGIN CERTIFICATE-----"),
+			},
+			expectedBindingTypes: map[string]binding.BindingType{"ca.crt": binding.TypeVolumeMount},
+		},
+		{
+			name: "swapped keys",
+			rename: []v1alpha1.KeyRename{
+				{From: "DATABASE_DBNAME", To: "DATABASE_IMAGE"},
+				{From: "DATABASE_IMAGE", To: "DATABASE_DBNAME"},
+			},
+			expected: map[string][]byte{
+				"DATABASE_DBCONNECTIONIP":   []byte("10.0.0.1"),
+				"DATABASE_DBCONNECTIONPORT": []byte("5432"),
+				"DATABASE_DBNAME":           []byte("postgres"),
+				"DATABASE_IMAGE":            []byte("orders"),
+				"DATABASE_TLS_CA_CRT":       []byte("-----BEGIN CERTIFICATE-----"),
+			},
+			expectedBindingTypes: bindingTypes,
+		},
+		{
+			name:        "invalid pattern",
+			include:     []string{"DATABASE_[DB"},
+			expectedErr: `Database testing/db: invalid key pattern "DATABASE_[DB": syntax error in pattern`,
+		},
+		{
+			name: "keys renamed to the same key",
+			rename: []v1alpha1.KeyRename{
+				{From: "DATABASE_DBCONNECTIONIP", To: "DB_HOST"},
+				{From: "DATABASE_DBNAME", To: "DB_HOST"},
+			},
+			expectedErr: `Database testing/db: keys "DATABASE_DBCONNECTIONIP" and "DATABASE_DBNAME" renamed to "DB_HOST"`,
+		},
+		{
+			name: "key renamed twice",
+			rename: []v1alpha1.KeyRename{
+				{From: "DATABASE_DBCONNECTIONIP", To: "DB_HOST"},
+				{From: "DATABASE_DBCONNECTIONIP", To: "DB_IP"},
+			},
+			expectedErr: `Database testing/db: key "DATABASE_DBCONNECTIONIP" renamed more than once`,
+		},
+		{
+			name:        "key renamed to a collected key",
+			rename:      []v1alpha1.KeyRename{{From: "DATABASE_DBCONNECTIONIP", To: "DATABASE_DBNAME"}},
+			expectedErr: `Database testing/db: key "DATABASE_DBCONNECTIONIP" renamed to "DATABASE_DBNAME", which is already collected`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svcCtx := &serviceContext{
+				service: mocks.UnstructuredDatabaseCRMock("testing", "db"),
+				include: tc.include,
+				exclude: tc.exclude,
+				rename:  tc.rename,
+			}
+			actual, actualBindingTypes, _, err := selectServiceKeys(log.NewLog("test"), svcCtx, envVars, bindingTypes)
+			if len(tc.expectedErr) > 0 {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+			require.Equal(t, tc.expectedBindingTypes, actualBindingTypes)
+		})
+	}
+}
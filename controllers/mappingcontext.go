@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/redhat-developer/service-binding-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxMappingContextFields is the maximum number of fields described for each service in the
+// status of the Service Binding.
+const maxMappingContextFields = 100
+
+// templateIdentifier matches the keys that can be referred by Go template field accessors.
+var templateIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// mappingContextPaths returns the paths the resource of the given service context is added to the
+// context of mapping templates at.
+func mappingContextPaths(svcCtx *serviceContext) [][]string {
+	gvk := svcCtx.service.GroupVersionKind()
+	paths := [][]string{
+		// allows the user to use the following expression:
+		//
+		// `{{ index . "v1alpha1" "postgresql.baiju.dev" "Database" "db-testing" "status" "connectionUrl" }}`
+		{gvk.Version, gvk.Group, gvk.Kind, svcCtx.service.GetName()},
+		// modified key names (group names have the "." separator changed to underbar and "-" in the
+		// resource name is changed to underbar "_" as well).
+		//
+		// `{{ .v1alpha1.postgresql_baiju_dev.Database.db_testing.status.connectionUrl }}`
+		createServiceIndexPath(svcCtx.service.GetName(), gvk),
+	}
+	// the informed 'id'.
+	//
+	// `{{ .db_testing.status.connectionUrl }}`
+	if svcCtx.id != nil {
+		paths = append(paths, []string{*svcCtx.id})
+	}
+	return paths
+}
+
+// templatePath returns the template expression referring to the given path of the context; e.g.
+// .status.host, or index .status.config "db.user" when keys aren't valid identifiers.
+func templatePath(path []string) string {
+	i := 0
+	for i < len(path) && templateIdentifier.MatchString(path[i]) {
+		i++
+	}
+	accessor := "." + strings.Join(path[:i], ".")
+	if i == len(path) {
+		return accessor
+	}
+	args := make([]string, 0, len(path)-i)
+	for _, p := range path[i:] {
+		args = append(args, strconv.Quote(p))
+	}
+	return fmt.Sprintf("index %s %s", accessor, strings.Join(args, " "))
+}
+
+// valueType returns the type reported for the given value of the context.
+func valueType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case int, int32, int64:
+		return "integer"
+	case float32, float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return "map"
+	case []interface{}, []string, []map[string]interface{}:
+		return "list"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// collectFields appends the fields found in obj, at any depth but not inside lists, to fields.
+func collectFields(obj map[string]interface{}, path []string, fields []v1alpha1.MappingContextField) []v1alpha1.MappingContextField {
+	for k, v := range obj {
+		// managed fields are bookkeeping of the API server, of no use to templates
+		if len(path) == 1 && path[0] == "metadata" && k == "managedFields" {
+			continue
+		}
+		p := append(append([]string{}, path...), k)
+		fields = append(fields, v1alpha1.MappingContextField{Path: templatePath(p), Type: valueType(v)})
+		if m, ok := v.(map[string]interface{}); ok {
+			fields = collectFields(m, p, fields)
+		}
+	}
+	return fields
+}
+
+// describeMappingContext returns the entries of the context of mapping templates built from the
+// services; only the types of their fields are described, never their values.
+func (sc serviceContextList) describeMappingContext() []v1alpha1.MappingContextService {
+	var services []v1alpha1.MappingContextService
+	for _, svcCtx := range sc {
+		gvk := svcCtx.service.GroupVersionKind()
+		desc := v1alpha1.MappingContextService{
+			GroupVersionKind: metav1.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind},
+			Name:             svcCtx.service.GetName(),
+		}
+		for _, p := range mappingContextPaths(svcCtx) {
+			desc.Paths = append(desc.Paths, templatePath(p))
+		}
+		fields := collectFields(svcCtx.service.Object, nil, nil)
+		sort.Slice(fields, func(i, j int) bool { return fields[i].Path < fields[j].Path })
+		if len(fields) > maxMappingContextFields {
+			fields = fields[:maxMappingContextFields]
+			desc.Truncated = true
+		}
+		desc.Fields = fields
+		services = append(services, desc)
+	}
+	return services
+}
@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/redhat-developer/service-binding-operator/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestTemplatePath(t *testing.T) {
+	for expected, path := range map[string][]string{
+		".status.host":                        {"status", "host"},
+		`index .status.dbConfigMap "db.user"`: {"status", "dbConfigMap", "db.user"},
+		`index .v1alpha1 "postgresql.baiju.dev" "Database" "db-demo"`: {"v1alpha1", "postgresql.baiju.dev", "Database", "db-demo"},
+		`index . "0" "host"`:                              {"0", "host"},
+		".v1alpha1.postgresql_baiju_dev.Database.db_demo": {"v1alpha1", "postgresql_baiju_dev", "Database", "db_demo"},
+	} {
+		require.Equal(t, expected, templatePath(path))
+	}
+}
+
+func TestDescribeMappingContext(t *testing.T) {
+	db := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "postgresql.baiju.dev/v1alpha1",
+		"kind":       "Database",
+		"metadata": map[string]interface{}{
+			"name":          "db-demo",
+			"managedFields": []interface{}{map[string]interface{}{"manager": "kubectl"}},
+		},
+		"status": map[string]interface{}{
+			"port":     int64(5432),
+			"ratio":    0.5,
+			"ready":    true,
+			"hosts":    []interface{}{"db-0"},
+			"password": "secret",
+			"config":   nil,
+			"dbConfigMap": map[string]interface{}{
+				"db.user": "admin",
+			},
+		},
+	}}
+	id := "db"
+
+	actual := serviceContextList{{service: db, id: &id}}.describeMappingContext()
+
+	require.Equal(t, []v1alpha1.MappingContextService{
+		{
+			GroupVersionKind: metav1.GroupVersionKind{Group: "postgresql.baiju.dev", Version: "v1alpha1", Kind: "Database"},
+			Name:             "db-demo",
+			Paths: []string{
+				`index .v1alpha1 "postgresql.baiju.dev" "Database" "db-demo"`,
+				".v1alpha1.postgresql_baiju_dev.Database.db_demo",
+				".db",
+			},
+			Fields: []v1alpha1.MappingContextField{
+				{Path: ".apiVersion", Type: "string"},
+				{Path: ".kind", Type: "string"},
+				{Path: ".metadata", Type: "map"},
+				{Path: ".metadata.name", Type: "string"},
+				{Path: ".status", Type: "map"},
+				{Path: ".status.config", Type: "null"},
+				{Path: ".status.dbConfigMap", Type: "map"},
+				{Path: ".status.hosts", Type: "list"},
+				{Path: ".status.password", Type: "string"},
+				{Path: ".status.port", Type: "integer"},
+				{Path: ".status.ratio", Type: "number"},
+				{Path: ".status.ready", Type: "boolean"},
+				{Path: `index .status.dbConfigMap "db.user"`, Type: "string"},
+			},
+		},
+	}, actual)
+
+	t.Run("fields are truncated", func(t *testing.T) {
+		spec := make(map[string]interface{})
+		for i := 0; i < maxMappingContextFields; i++ {
+			spec[fmt.Sprintf("field%03d", i)] = "value"
+		}
+		db := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "postgresql.baiju.dev/v1alpha1",
+			"kind":       "Database",
+			"spec":       spec,
+		}}
+
+		actual := serviceContextList{{service: db}}.describeMappingContext()
+
+		require.Len(t, actual, 1)
+		require.Len(t, actual[0].Fields, maxMappingContextFields)
+		require.True(t, actual[0].Truncated)
+	})
+}
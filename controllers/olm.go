@@ -17,6 +17,7 @@ import (
 	"k8s.io/client-go/dynamic"
 
 	"github.com/redhat-developer/service-binding-operator/pkg/log"
+	"github.com/redhat-developer/service-binding-operator/pkg/nested/accumulator"
 )
 
 // olm represents the actions this operator needs to take upon Operator-Lifecycle-Manager resources,
@@ -125,13 +126,27 @@ func (o *olm) selectCRDByGVK(gvk schema.GroupVersionKind, crd *unstructured.Unst
 		log.Error(err, "on listing owned CRDs")
 		return nil, err
 	}
+	return o.selectCRDDescription(gvk, crd, ownedCRDs)
+}
+
+// selectCRDDescription return a single CRDDescription based on a given GVK, searching the given
+// CSV owned CRDDescriptions and merging the descriptors declared in the CRD.
+func (o *olm) selectCRDDescription(
+	gvk schema.GroupVersionKind,
+	crd *unstructured.Unstructured,
+	ownedCRDs []unstructured.Unstructured,
+) (*olmv1alpha1.CRDDescription, error) {
+	log := o.logger.WithValues("Selector.GVK", gvk)
 
-	var crdDescription *olmv1alpha1.CRDDescription
+	var (
+		crdDescription *olmv1alpha1.CRDDescription
+		err            error
+	)
 
 	// CRDDescription is both used by OLM to configure OLM descriptors in manifests existing in the
 	// cluster but is also built from annotations present in the CRD
 	if crd != nil {
-		crdDescription, err = buildCRDDescriptionFromCRD(crd)
+		crdDescription, err = buildCRDDescriptionFromCRD(crd, gvk.Version)
 		if err != nil {
 			return nil, err
 		}
@@ -168,13 +183,20 @@ func (o *olm) selectCRDByGVK(gvk schema.GroupVersionKind, crd *unstructured.Unst
 	} else if len(crdDescriptions) == 0 {
 		// use the crdDescription built from CRD annotations as fallback
 		return crdDescription, nil
+	} else if crdDescription != nil {
+		// descriptors declared in the CRD override the ones declared in the CSV
+		return mergeCRDDescriptions(crdDescriptions[0], crdDescription), nil
 	}
 
 	return crdDescriptions[0], nil
 }
 
-// buildCRDDescriptionFromCRD builds a CRDDescription from annotations present in the CRD.
-func buildCRDDescriptionFromCRD(crd *unstructured.Unstructured) (*olmv1alpha1.CRDDescription, error) {
+// buildCRDDescriptionFromCRD builds a CRDDescription for the given served version from annotations
+// present in the CRD.
+func buildCRDDescriptionFromCRD(
+	crd *unstructured.Unstructured,
+	version string,
+) (*olmv1alpha1.CRDDescription, error) {
 	var (
 		ok  bool
 		err error
@@ -189,12 +211,14 @@ func buildCRDDescriptionFromCRD(crd *unstructured.Unstructured) (*olmv1alpha1.CR
 		return nil, err
 	}
 
-	crdDescription.Version, ok, err = unstructured.NestedString(crd.Object, "spec", "version")
-	if err != nil || !ok {
+	served, err := isCRDVersionServed(crd, version)
+	if err != nil || !served {
 		return nil, err
 	}
+	crdDescription.Version = version
 
-	specDescriptors, statusDescriptors, err := buildDescriptorsFromAnnotations(crd.GetAnnotations())
+	specDescriptors, statusDescriptors, err := buildDescriptorsFromAnnotations(
+		crdBindingAnnotations(crd, version))
 	if err != nil {
 		return nil, err
 	}
@@ -204,23 +228,70 @@ func buildCRDDescriptionFromCRD(crd *unstructured.Unstructured) (*olmv1alpha1.CR
 	return crdDescription, nil
 }
 
+// isCRDVersionServed returns true if the given version is present in the CRD's "spec.versions" and
+// is being served.
+func isCRDVersionServed(crd *unstructured.Unstructured, version string) (bool, error) {
+	versions, _, err := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	if err != nil {
+		return false, err
+	}
+	for _, v := range versions {
+		m, ok := v.(map[string]interface{})
+		if !ok || m["name"] != version {
+			continue
+		}
+		served, _, err := unstructured.NestedBool(m, "served")
+		return served, err
+	}
+	return false, nil
+}
+
+// descriptorsAnnotationPrefix is the prefix of CRD annotations declaring OLM descriptors, in the
+// form "service.binding.descriptors/<spec|status>.<path>: <x-descriptor>[,<x-descriptor>...]", for
+// example:
+//
+//	service.binding.descriptors/status.dbCredentials: urn:alm:descriptor:io.kubernetes:Secret,service.binding:username:sourceKey=username
+const descriptorsAnnotationPrefix = "service.binding.descriptors/"
+
 // buildDescriptorsFromAnnotations builds two descriptors collection, one for spec descriptors and
-// another for status descriptors.
+// another for status descriptors, from the descriptors annotations present in the given map.
 func buildDescriptorsFromAnnotations(in map[string]string) (
 	[]olmv1alpha1.SpecDescriptor,
 	[]olmv1alpha1.StatusDescriptor,
 	error,
 ) {
-	// TODO: check if this function is no-op (it looks like that)
 	var specDescriptors []olmv1alpha1.SpecDescriptor
 	var statusDescriptors []olmv1alpha1.StatusDescriptor
 
 	acc := make(map[string][]string)
 
-	// create the status and/or spec descriptors based on the
+	// accumulate the x-descriptors declared for each field path
+	for k, v := range in {
+		if !strings.HasPrefix(k, descriptorsAnnotationPrefix) {
+			continue
+		}
+		fieldPath := strings.TrimPrefix(k, descriptorsAnnotationPrefix)
+		a := accumulator.NewAccumulator()
+		for _, d := range strings.Split(v, ",") {
+			if d = strings.TrimSpace(d); len(d) == 0 {
+				continue
+			}
+			if err := a.Accumulate(d); err != nil {
+				return nil, nil, err
+			}
+		}
+		if descriptors, ok := a.Value().([]string); ok {
+			acc[fieldPath] = descriptors
+		}
+	}
+
+	// create the status and/or spec descriptors based on the accumulated x-descriptors
 	for fieldPath, descriptors := range acc {
 		sort.Strings(descriptors)
-		path := strings.Split(fieldPath, ".")
+		path := strings.SplitN(fieldPath, ".", 2)
+		if len(path) < 2 || len(path[1]) == 0 {
+			return nil, nil, fmt.Errorf("invalid descriptor path %q", fieldPath)
+		}
 		if path[0] == "status" {
 			statusDescriptors = append(statusDescriptors, olmv1alpha1.StatusDescriptor{
 				Path:         path[1],
@@ -231,12 +302,55 @@ func buildDescriptorsFromAnnotations(in map[string]string) (
 				Path:         path[1],
 				XDescriptors: descriptors,
 			})
+		} else {
+			return nil, nil, fmt.Errorf("invalid descriptor path %q: should start with spec or status", fieldPath)
 		}
 	}
 
+	// keep the output stable regardless of map iteration order
+	sort.Slice(specDescriptors, func(i, j int) bool {
+		return specDescriptors[i].Path < specDescriptors[j].Path
+	})
+	sort.Slice(statusDescriptors, func(i, j int) bool {
+		return statusDescriptors[i].Path < statusDescriptors[j].Path
+	})
+
 	return specDescriptors, statusDescriptors, nil
 }
 
+// mergeCRDDescriptions merges the descriptors built from CRD annotations into the CRDDescription
+// found in a CSV; CRD descriptors take precedence over CSV descriptors declared for the same path.
+func mergeCRDDescriptions(
+	csvDescription *olmv1alpha1.CRDDescription,
+	crdDescription *olmv1alpha1.CRDDescription,
+) *olmv1alpha1.CRDDescription {
+	merged := csvDescription.DeepCopy()
+
+SPEC:
+	for _, d := range crdDescription.SpecDescriptors {
+		for i := range merged.SpecDescriptors {
+			if merged.SpecDescriptors[i].Path == d.Path {
+				merged.SpecDescriptors[i] = d
+				continue SPEC
+			}
+		}
+		merged.SpecDescriptors = append(merged.SpecDescriptors, d)
+	}
+
+STATUS:
+	for _, d := range crdDescription.StatusDescriptors {
+		for i := range merged.StatusDescriptors {
+			if merged.StatusDescriptors[i].Path == d.Path {
+				merged.StatusDescriptors[i] = d
+				continue STATUS
+			}
+		}
+		merged.StatusDescriptors = append(merged.StatusDescriptors, d)
+	}
+
+	return merged
+}
+
 // extractGVKs loop owned objects and extract the GVK information from them.
 func (o *olm) extractGVKs(
 	crdDescriptions []unstructured.Unstructured,
@@ -95,6 +95,39 @@ func TestOLMNew(t *testing.T) {
 		require.Equal(t, expectedCRDName, crd.Name)
 	})
 
+	t.Run("SelectCRDByGVK with CRD descriptors", func(t *testing.T) {
+		crd, err := mocks.UnstructuredDatabaseCRDMock(ns)
+		require.NoError(t, err)
+		crd.SetAnnotations(map[string]string{
+			"service.binding.descriptors/status.dbCredentials": "urn:alm:descriptor:io.kubernetes:Secret,service.binding:password:sourceKey=password",
+			"service.binding.descriptors/spec.dbPort":          "service.binding:port",
+		})
+
+		crdDescription, err := olm.selectCRDByGVK(schema.GroupVersionKind{
+			Group:   mocks.CRDName,
+			Version: mocks.CRDVersion,
+			Kind:    mocks.CRDKind,
+		}, crd)
+		require.NoError(t, err)
+		require.NotNil(t, crdDescription)
+
+		// CSV descriptors are kept, unless overridden by CRD descriptors
+		require.Equal(t, []olmv1alpha1.SpecDescriptor{
+			mocks.DBNameSpecDesc,
+			mocks.ImageSpecDesc,
+			{Path: "dbPort", XDescriptors: []string{"service.binding:port"}},
+		}, crdDescription.SpecDescriptors)
+		require.Equal(t, []olmv1alpha1.StatusDescriptor{
+			{
+				Path: "dbCredentials",
+				XDescriptors: []string{
+					"service.binding:password:sourceKey=password",
+					"urn:alm:descriptor:io.kubernetes:Secret",
+				},
+			},
+		}, crdDescription.StatusDescriptors)
+	})
+
 	t.Run("ListCSVOwnedCRDsAsGVKs", func(t *testing.T) {
 		gvks, err := olm.listCSVOwnedCRDsAsGVKs()
 		require.NoError(t, err)
@@ -110,3 +143,118 @@ func TestOLMNew(t *testing.T) {
 		assertGVKs(t, gvks)
 	})
 }
+
+func TestBuildCRDDescriptionFromCRD(t *testing.T) {
+	crd, err := mocks.UnstructuredDatabaseCRDMock("")
+	require.NoError(t, err)
+
+	t.Run("served version", func(t *testing.T) {
+		crdDescription, err := buildCRDDescriptionFromCRD(crd, mocks.CRDVersion)
+		require.NoError(t, err)
+		require.NotNil(t, crdDescription)
+		require.Equal(t, crd.GetName(), crdDescription.Name)
+		require.Equal(t, mocks.CRDKind, crdDescription.Kind)
+		require.Equal(t, mocks.CRDVersion, crdDescription.Version)
+	})
+
+	t.Run("version not served", func(t *testing.T) {
+		crdDescription, err := buildCRDDescriptionFromCRD(crd, "v2")
+		require.NoError(t, err)
+		require.Nil(t, crdDescription)
+	})
+}
+
+func TestBuildDescriptorsFromAnnotations(t *testing.T) {
+	type testCase struct {
+		name              string
+		annotations       map[string]string
+		expectedSpec      []olmv1alpha1.SpecDescriptor
+		expectedStatus    []olmv1alpha1.StatusDescriptor
+		expectedErrorText string
+	}
+
+	testCases := []testCase{
+		{
+			name: "unrelated annotations are ignored",
+			annotations: map[string]string{
+				"service.binding/username": "path={.status.username}",
+				"example.com/descriptors":  "service.binding:username",
+			},
+		},
+		{
+			name: "spec and status descriptors",
+			annotations: map[string]string{
+				"service.binding.descriptors/status.dbCredentials": "urn:alm:descriptor:io.kubernetes:Secret, service.binding:username:sourceKey=username",
+				"service.binding.descriptors/status.address.host":  "service.binding:host",
+				"service.binding.descriptors/spec.dbName":          "service.binding:dbName",
+			},
+			expectedSpec: []olmv1alpha1.SpecDescriptor{
+				{Path: "dbName", XDescriptors: []string{"service.binding:dbName"}},
+			},
+			expectedStatus: []olmv1alpha1.StatusDescriptor{
+				{Path: "address.host", XDescriptors: []string{"service.binding:host"}},
+				{
+					Path: "dbCredentials",
+					XDescriptors: []string{
+						"service.binding:username:sourceKey=username",
+						"urn:alm:descriptor:io.kubernetes:Secret",
+					},
+				},
+			},
+		},
+		{
+			name: "path outside spec and status",
+			annotations: map[string]string{
+				"service.binding.descriptors/metadata.name": "service.binding:name",
+			},
+			expectedErrorText: `invalid descriptor path "metadata.name": should start with spec or status`,
+		},
+		{
+			name: "path without field",
+			annotations: map[string]string{
+				"service.binding.descriptors/status": "service.binding:status",
+			},
+			expectedErrorText: `invalid descriptor path "status"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec, status, err := buildDescriptorsFromAnnotations(tc.annotations)
+			if tc.expectedErrorText != "" {
+				require.EqualError(t, err, tc.expectedErrorText)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedSpec, spec)
+			require.Equal(t, tc.expectedStatus, status)
+		})
+	}
+}
+
+func TestMergeCRDDescriptions(t *testing.T) {
+	csvDescription := &olmv1alpha1.CRDDescription{
+		Name:              "databases.postgresql.baiju.dev",
+		SpecDescriptors:   []olmv1alpha1.SpecDescriptor{mocks.DBNameSpecDesc},
+		StatusDescriptors: []olmv1alpha1.StatusDescriptor{mocks.DBPasswordCredentialsOnEnvStatusDesc},
+	}
+	crdDescription := &olmv1alpha1.CRDDescription{
+		SpecDescriptors: []olmv1alpha1.SpecDescriptor{
+			{Path: "dbName", XDescriptors: []string{"service.binding:dbName"}},
+		},
+		StatusDescriptors: []olmv1alpha1.StatusDescriptor{
+			{Path: "dbHost", XDescriptors: []string{"service.binding:host"}},
+		},
+	}
+
+	merged := mergeCRDDescriptions(csvDescription, crdDescription)
+
+	require.Equal(t, csvDescription.Name, merged.Name)
+	require.Equal(t, crdDescription.SpecDescriptors, merged.SpecDescriptors)
+	require.Equal(t, []olmv1alpha1.StatusDescriptor{
+		mocks.DBPasswordCredentialsOnEnvStatusDesc,
+		{Path: "dbHost", XDescriptors: []string{"service.binding:host"}},
+	}, merged.StatusDescriptors)
+	// the CSV description is left untouched
+	require.Equal(t, []olmv1alpha1.SpecDescriptor{mocks.DBNameSpecDesc}, csvDescription.SpecDescriptors)
+}
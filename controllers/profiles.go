@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/redhat-developer/service-binding-operator/pkg/binding"
+	"github.com/redhat-developer/service-binding-operator/pkg/log"
+)
+
+const (
+	// ProfileLabel marks the ConfigMaps in the operator namespace holding binding profiles; every
+	// data entry of those is a YAML list of profiles.
+	ProfileLabel = "service.binding/profile"
+	// profilesResyncPeriod is the period the profiles are reloaded regardless of ConfigMap changes.
+	profilesResyncPeriod = 10 * time.Minute
+)
+
+var configMapsGVR = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "configmaps"}
+
+// ProfileLoader keeps binding.DefaultProfileRegistry up to date with the profiles declared in
+// ConfigMaps labeled with ProfileLabel in the operator namespace.
+type ProfileLoader struct {
+	// Namespace is the namespace the ConfigMaps are loaded from.
+	Namespace string
+
+	client   dynamic.Interface        // kubernetes dynamic api client
+	registry *binding.ProfileRegistry // registry the profiles are loaded into
+	logger   *log.Log                 // logger instance
+}
+
+// SetupWithManager registers the loader to be started by the Manager.
+func (l *ProfileLoader) SetupWithManager(mgr ctrl.Manager) error {
+	client, err := dynamic.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	l.client = client
+	l.registry = binding.DefaultProfileRegistry
+	l.logger = log.NewLog("profiles")
+	return mgr.Add(l)
+}
+
+// NeedLeaderElection returns false, since profiles should be loaded by every replica.
+func (l *ProfileLoader) NeedLeaderElection() bool {
+	return false
+}
+
+// Start watches the profile ConfigMaps, reloading the profiles until the stop channel is closed.
+func (l *ProfileLoader) Start(stop <-chan struct{}) error {
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(
+		l.client, profilesResyncPeriod, l.Namespace, func(opts *metav1.ListOptions) {
+			opts.LabelSelector = ProfileLabel + "=true"
+		})
+	informer := factory.ForResource(configMapsGVR)
+
+	reload := func() {
+		objs, err := informer.Lister().List(labels.Everything())
+		if err != nil {
+			l.logger.Error(err, "On listing profile ConfigMaps")
+			return
+		}
+		configMaps := make([]*unstructured.Unstructured, 0, len(objs))
+		for _, obj := range objs {
+			if u, ok := obj.(*unstructured.Unstructured); ok {
+				configMaps = append(configMaps, u)
+			}
+		}
+		l.registry.ReplaceSources(profilesFromConfigMaps(l.logger, configMaps))
+	}
+	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { reload() },
+		UpdateFunc: func(interface{}, interface{}) { reload() },
+		DeleteFunc: func(interface{}) { reload() },
+	})
+
+	factory.Start(stop)
+	<-stop
+	return nil
+}
+
+// profilesFromConfigMaps parses the profiles declared in the given ConfigMaps, keyed by the
+// ConfigMap name; ConfigMaps with invalid profiles are skipped as a whole.
+func profilesFromConfigMaps(logger *log.Log, configMaps []*unstructured.Unstructured) map[string][]binding.Profile {
+	sources := make(map[string][]binding.Profile)
+	for _, cm := range configMaps {
+		profiles, err := parseProfileConfigMap(cm)
+		if err != nil {
+			logger.Error(err, "Skipping invalid profile ConfigMap", "ConfigMap", cm.GetName())
+			continue
+		}
+		sources[cm.GetName()] = profiles
+	}
+	return sources
+}
+
+// parseProfileConfigMap parses the profiles declared in the data entries of the given ConfigMap, in
+// the lexical order of its keys.
+func parseProfileConfigMap(cm *unstructured.Unstructured) ([]binding.Profile, error) {
+	data, _, err := unstructured.NestedStringMap(cm.Object, "data")
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var profiles []binding.Profile
+	for _, k := range keys {
+		p, err := binding.ParseProfiles([]byte(data[k]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid profiles in key %q: %v", k, err)
+		}
+		profiles = append(profiles, p...)
+	}
+	return profiles, nil
+}
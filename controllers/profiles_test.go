@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/redhat-developer/service-binding-operator/pkg/binding"
+	"github.com/redhat-developer/service-binding-operator/pkg/log"
+)
+
+func profileConfigMap(name string, data map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name":   name,
+				"labels": map[string]interface{}{ProfileLabel: "true"},
+			},
+			"data": data,
+		},
+	}
+}
+
+func TestProfilesFromConfigMaps(t *testing.T) {
+	logger := log.NewLog("testProfilesFromConfigMaps")
+
+	sources := profilesFromConfigMaps(logger, []*unstructured.Unstructured{
+		profileConfigMap("cache-profiles", map[string]interface{}{
+			"redis.yaml": `
+- group: cache.example.com
+  kind: Redis
+  annotations:
+    service.binding/host: path={.status.host}
+`,
+			"memcached.yaml": `
+- group: cache.example.com
+  kind: Memcached
+  annotations:
+    service.binding/host: path={.status.address}
+`,
+		}),
+		profileConfigMap("invalid-profiles", map[string]interface{}{
+			"queue.yaml": `
+- group: queue.example.com
+  kind: Queue
+  annotations:
+    service.binding/url: path={.status.url
+`,
+		}),
+	})
+
+	require.Equal(t, map[string][]binding.Profile{
+		"cache-profiles": {
+			{
+				Group:       "cache.example.com",
+				Kind:        "Memcached",
+				Annotations: map[string]string{"service.binding/host": "path={.status.address}"},
+			},
+			{
+				Group:       "cache.example.com",
+				Kind:        "Redis",
+				Annotations: map[string]string{"service.binding/host": "path={.status.host}"},
+			},
+		},
+	}, sources)
+}
@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/redhat-developer/service-binding-operator/api/v1alpha1"
+	"github.com/redhat-developer/service-binding-operator/pkg/binding"
+)
+
+// errBindingProviderFailed is returned when the binding provider of a service can't provide the
+// binding values.
+type errBindingProviderFailed struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+	name      string
+	err       error
+}
+
+func (e errBindingProviderFailed) Error() string {
+	return fmt.Sprintf("binding provider of service %s %s/%s failed: %v",
+		e.gvk.Kind, e.namespace, e.name, e.err)
+}
+
+func (e errBindingProviderFailed) Unwrap() error {
+	return e.err
+}
+
+// bindingProviderContext holds the binding provider and the details of the Service Binding sent to
+// it for the services being bound.
+type bindingProviderContext struct {
+	provider binding.BindingProvider
+	binding  binding.ProviderBinding
+}
+
+// newBindingProviderContext returns the context to call the given binding provider for the
+// services of the given Service Binding; nil is returned when there is no provider.
+func newBindingProviderContext(
+	provider binding.BindingProvider,
+	sbr *v1alpha1.ServiceBinding,
+) *bindingProviderContext {
+	if provider == nil {
+		return nil
+	}
+	ctx := &bindingProviderContext{
+		provider: provider,
+		binding: binding.ProviderBinding{
+			Namespace: sbr.GetNamespace(),
+			Name:      sbr.GetName(),
+		},
+	}
+	if app := sbr.Spec.Application; app != nil {
+		ctx.binding.Application = &binding.ProviderApplication{
+			Group:    app.Group,
+			Version:  app.Version,
+			Resource: app.Resource,
+			Name:     app.Name,
+		}
+	}
+	return ctx
+}
+
+// provide requests the binding values of the given service from the endpoint.
+func (c *bindingProviderContext) provide(
+	endpoint string,
+	obj *unstructured.Unstructured,
+	id *string,
+) (map[string]string, error) {
+	service := obj.DeepCopy()
+	// managed fields are only noise to providers
+	unstructured.RemoveNestedField(service.Object, "metadata", "managedFields")
+
+	req := &binding.ProviderRequest{
+		Service: service.Object,
+		Binding: c.binding,
+	}
+	req.Binding.ServiceID = stringValueOrDefault(id, "")
+	return c.provider.Provide(endpoint, req)
+}
+
+// newBindingProvider returns the binding provider configured through the command line flags.
+func newBindingProvider() (binding.BindingProvider, error) {
+	var allowedURLs []string
+	for _, u := range strings.Split(bindingProviderAllowedURLs, ",") {
+		if u = strings.TrimSpace(u); len(u) > 0 {
+			allowedURLs = append(allowedURLs, u)
+		}
+	}
+
+	var tlsConfig *tls.Config
+	if len(bindingProviderCAFile) > 0 {
+		pem, err := ioutil.ReadFile(bindingProviderCAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", bindingProviderCAFile)
+		}
+		tlsConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	return binding.NewHTTPBindingProvider(binding.HTTPProviderOptions{
+		AllowedURLs: allowedURLs,
+		Timeout:     bindingProviderTimeout,
+		TLSConfig:   tlsConfig,
+		CacheTTL:    bindingProviderCacheTTL,
+	}), nil
+}
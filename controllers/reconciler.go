@@ -16,6 +16,7 @@ import (
 
 	"github.com/redhat-developer/service-binding-operator/api/v1alpha1"
 	"github.com/redhat-developer/service-binding-operator/pkg/converter"
+	"github.com/redhat-developer/service-binding-operator/pkg/log"
 )
 
 // getServiceBinding retrieve the SBR object based on namespaced-name.
@@ -123,44 +124,55 @@ func (r *ServiceBindingReconciler) doReconcile(request reconcile.Request) (recon
 			sbr.GetNamespace(),
 			sbr.Spec.Services,
 			sbr.Spec.DetectBindingResources,
+			sbr.Spec.DetectBindingFields,
+			newBindingProviderContext(r.bindingProvider, sbr),
 			r.restMapper,
 		)
 		if err != nil {
-			//handle service not found error
-			if k8serrors.IsNotFound(err) {
-				err = updateSBRConditions(r.dynClient, sbr,
-					metav1.Condition{
-						Type:    v1alpha1.CollectionReady,
-						Status:  metav1.ConditionFalse,
-						Reason:  v1alpha1.ServiceNotFoundReason,
-						Message: err.Error(),
-					},
-					metav1.Condition{
-						Type:   v1alpha1.InjectionReady,
-						Status: metav1.ConditionFalse,
-					},
-					metav1.Condition{
-						Type:   v1alpha1.BindingReady,
-						Status: metav1.ConditionFalse,
-					},
-				)
-				if err != nil {
-					logger.Error(err, "Failed to update SBR conditions", "sbr", sbr)
-				}
+			var requiredErr errRequiredValuesNotFound
+			var profileErr errCredentialProfileNotFound
+			var providerErr errBindingProviderFailed
+			var definitionErr errBindingDefinitionsFailed
+			switch {
+			case errors.As(err, &definitionErr):
+				// the annotations have to be fixed, or the resources they refer to provided
+				return r.failCollection(logger, sbr, v1alpha1.BindingDefinitionFailedReason, definitionErr)
+			case k8serrors.IsNotFound(err):
+				return r.failCollection(logger, sbr, v1alpha1.ServiceNotFoundReason, err)
+			case errors.As(err, &requiredErr):
+				// values required by the service's binding annotations are missing; it is expected
+				// those will be eventually populated by the service
+				return r.failCollection(logger, sbr, v1alpha1.RequiredBindingValueNotFoundReason, requiredErr)
+			case errors.As(err, &profileErr):
+				// the selected credential profile might be declared once the service is updated
+				return r.failCollection(logger, sbr, v1alpha1.CredentialProfileNotFoundReason, profileErr)
+			case errors.As(err, &providerErr):
+				// the binding provider might recover, or be fixed, later on
+				return r.failCollection(logger, sbr, v1alpha1.BindingProviderFailedReason, providerErr)
 			}
 			return requeueError(err)
 
 		}
 	}
-	binding, err := buildBinding(
-		r.dynClient,
-		sbr.Spec.Mappings,
-		serviceCtxs,
-		sbr.Spec.NamePrefix,
-	)
-	if err != nil {
+	sbr.Status.InferredBindings = serviceCtxs.getInferredBindings()
+	sbr.Status.MappingContext = serviceCtxs.describeMappingContext()
+
+	binding, err := buildBinding(r.dynClient, serviceCtxs, newBindingOptions(sbr))
+	collisionErr := errKeyCollision{}
+	templateErr := errMappingTemplate{}
+	switch {
+	case errors.As(err, &collisionErr):
+		// the colliding services or mappings have to be changed, or a different policy chosen
+		sbr.Status.KeyCollisions = collisionErr.collisions
+		return r.failCollection(logger, sbr, v1alpha1.KeyCollisionReason, collisionErr)
+	case errors.As(err, &templateErr):
+		// the mapping has to be fixed, or the values it refers to provided
+		return r.failCollection(logger, sbr, v1alpha1.MappingTemplateErrorReason, templateErr)
+	case err != nil:
 		return requeueError(err)
 	}
+	sbr.Status.KeyCollisions = binding.collisions
+	sbr.Status.SanitizedKeys = binding.sanitizedKeys
 
 	options := &serviceBinderOptions{
 		dynClient:              r.dynClient,
@@ -229,6 +241,36 @@ func (r *ServiceBindingReconciler) doReconcile(request reconcile.Request) (recon
 	return sb.bind()
 }
 
+// failCollection reports the binding values of the given Service Binding can't be collected for
+// the given reason, the binding being neither injected nor ready, and requeues err.
+func (r *ServiceBindingReconciler) failCollection(
+	logger *log.Log,
+	sbr *v1alpha1.ServiceBinding,
+	reason string,
+	err error,
+) (reconcile.Result, error) {
+	updateErr := updateSBRConditions(r.dynClient, sbr,
+		metav1.Condition{
+			Type:    v1alpha1.CollectionReady,
+			Status:  metav1.ConditionFalse,
+			Reason:  reason,
+			Message: err.Error(),
+		},
+		metav1.Condition{
+			Type:   v1alpha1.InjectionReady,
+			Status: metav1.ConditionFalse,
+		},
+		metav1.Condition{
+			Type:   v1alpha1.BindingReady,
+			Status: metav1.ConditionFalse,
+		},
+	)
+	if updateErr != nil {
+		logger.Error(updateErr, "Failed to update SBR conditions", "sbr", sbr)
+	}
+	return requeueError(err)
+}
+
 func updateSBRConditions(dynClient dynamic.Interface, sbr *v1alpha1.ServiceBinding, conditions ...metav1.Condition) error {
 	for _, v := range conditions {
 		meta.SetStatusCondition(&sbr.Status.Conditions, v)
@@ -313,6 +313,111 @@ func TestServiceNotFound(t *testing.T) {
 	require.Equal(t, 1, len(sbrOutput2.Status.Applications))
 }
 
+func TestRequiredBindingValueNotFound(t *testing.T) {
+	backingServiceResourceRef := "backingServiceRef"
+	applicationResourceRef := "applicationRef"
+	f := mocks.NewFake(t, reconcilerNs)
+	f.AddMockedUnstructuredServiceBinding(reconcilerName, backingServiceResourceRef, applicationResourceRef, deploymentsGVR, nil)
+	f.AddMockedUnstructuredDeployment(applicationResourceRef, nil)
+	cr := f.AddMockedDatabaseCR(backingServiceResourceRef, reconcilerNs).(*unstructured.Unstructured)
+	cr.SetAnnotations(map[string]string{
+		"service.binding/password": "path={.status.password}",
+	})
+
+	fakeDynClient := f.FakeDynClient()
+	mapper := testutils.BuildTestRESTMapper()
+	r := &ServiceBindingReconciler{dynClient: fakeDynClient, restMapper: mapper, Scheme: f.S}
+	r.resourceWatcher = newFakeResourceWatcher(mapper)
+
+	res, err := r.Reconcile(reconcileRequest())
+	require.Error(t, err)
+	require.True(t, res.Requeue)
+
+	namespacedName := types.NamespacedName{Namespace: reconcilerNs, Name: reconcilerName}
+	sbrOutput, err := r.getServiceBinding(namespacedName)
+	require.NoError(t, err)
+
+	requireConditionPresentAndFalse(t, v1alpha1.CollectionReady, sbrOutput.Status.Conditions)
+	requireConditionPresentAndFalse(t, v1alpha1.InjectionReady, sbrOutput.Status.Conditions)
+	requireConditionPresentAndFalse(t, v1alpha1.BindingReady, sbrOutput.Status.Conditions)
+
+	cond := meta.FindStatusCondition(sbrOutput.Status.Conditions, v1alpha1.CollectionReady)
+	require.Equal(t, v1alpha1.RequiredBindingValueNotFoundReason, cond.Reason)
+	require.Contains(t, cond.Message, backingServiceResourceRef)
+	require.Contains(t, cond.Message, "status.password")
+}
+
+func TestBindingDefinitionFailed(t *testing.T) {
+	backingServiceResourceRef := "backingServiceRef"
+	applicationResourceRef := "applicationRef"
+	f := mocks.NewFake(t, reconcilerNs)
+	f.AddMockedUnstructuredServiceBinding(reconcilerName, backingServiceResourceRef, applicationResourceRef, deploymentsGVR, nil)
+	f.AddMockedUnstructuredDeployment(applicationResourceRef, nil)
+	cr := f.AddMockedDatabaseCR(backingServiceResourceRef, reconcilerNs).(*unstructured.Unstructured)
+	cr.SetAnnotations(map[string]string{
+		"service.binding/password": "path={.status.password},elementType=bogus",
+	})
+
+	fakeDynClient := f.FakeDynClient()
+	mapper := testutils.BuildTestRESTMapper()
+	r := &ServiceBindingReconciler{dynClient: fakeDynClient, restMapper: mapper, Scheme: f.S}
+	r.resourceWatcher = newFakeResourceWatcher(mapper)
+
+	res, err := r.Reconcile(reconcileRequest())
+	require.Error(t, err)
+	require.True(t, res.Requeue)
+
+	namespacedName := types.NamespacedName{Namespace: reconcilerNs, Name: reconcilerName}
+	sbrOutput, err := r.getServiceBinding(namespacedName)
+	require.NoError(t, err)
+
+	requireConditionPresentAndFalse(t, v1alpha1.CollectionReady, sbrOutput.Status.Conditions)
+	requireConditionPresentAndFalse(t, v1alpha1.BindingReady, sbrOutput.Status.Conditions)
+
+	cond := meta.FindStatusCondition(sbrOutput.Status.Conditions, v1alpha1.CollectionReady)
+	require.Equal(t, v1alpha1.BindingDefinitionFailedReason, cond.Reason)
+	require.Contains(t, cond.Message, backingServiceResourceRef)
+	require.Contains(t, cond.Message, "service.binding/password")
+}
+
+func TestCredentialProfileNotFound(t *testing.T) {
+	backingServiceResourceRef := "backingServiceRef"
+	applicationResourceRef := "applicationRef"
+	f := mocks.NewFake(t, reconcilerNs)
+	sbr := f.AddMockedUnstructuredServiceBinding(reconcilerName, backingServiceResourceRef, applicationResourceRef, deploymentsGVR, nil)
+	services, _, err := unstructured.NestedSlice(sbr.Object, "spec", "services")
+	require.NoError(t, err)
+	services[0].(map[string]interface{})["profile"] = "admin"
+	require.NoError(t, unstructured.SetNestedSlice(sbr.Object, services, "spec", "services"))
+	f.AddMockedUnstructuredDeployment(applicationResourceRef, nil)
+	cr := f.AddMockedDatabaseCR(backingServiceResourceRef, reconcilerNs).(*unstructured.Unstructured)
+	cr.SetAnnotations(map[string]string{
+		"service.binding.profile/readonly/password": "path={.status.readonlyPassword}",
+	})
+
+	fakeDynClient := f.FakeDynClient()
+	mapper := testutils.BuildTestRESTMapper()
+	r := &ServiceBindingReconciler{dynClient: fakeDynClient, restMapper: mapper, Scheme: f.S}
+	r.resourceWatcher = newFakeResourceWatcher(mapper)
+
+	res, err := r.Reconcile(reconcileRequest())
+	require.Error(t, err)
+	require.True(t, res.Requeue)
+
+	namespacedName := types.NamespacedName{Namespace: reconcilerNs, Name: reconcilerName}
+	sbrOutput, err := r.getServiceBinding(namespacedName)
+	require.NoError(t, err)
+
+	requireConditionPresentAndFalse(t, v1alpha1.CollectionReady, sbrOutput.Status.Conditions)
+	requireConditionPresentAndFalse(t, v1alpha1.InjectionReady, sbrOutput.Status.Conditions)
+	requireConditionPresentAndFalse(t, v1alpha1.BindingReady, sbrOutput.Status.Conditions)
+
+	cond := meta.FindStatusCondition(sbrOutput.Status.Conditions, v1alpha1.CollectionReady)
+	require.Equal(t, v1alpha1.CredentialProfileNotFoundReason, cond.Reason)
+	require.Contains(t, cond.Message, backingServiceResourceRef)
+	require.Contains(t, cond.Message, "admin")
+}
+
 func TestApplicationNotFound(t *testing.T) {
 	backingServiceResourceRef := "backingService1"
 	matchLabels := map[string]string{
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"fmt"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -8,7 +9,9 @@ import (
 	"k8s.io/client-go/dynamic"
 
 	"github.com/redhat-developer/service-binding-operator/api/v1alpha1"
+	"github.com/redhat-developer/service-binding-operator/pkg/binding"
 	"github.com/redhat-developer/service-binding-operator/pkg/envvars"
+	"github.com/redhat-developer/service-binding-operator/pkg/flavor"
 	"github.com/redhat-developer/service-binding-operator/pkg/log"
 )
 
@@ -32,7 +35,9 @@ func createServiceIndexPath(name string, gvk schema.GroupVersionKind) []string {
 
 }
 
-func buildServiceEnvVars(svcCtx *serviceContext, globalNamePrefix string) (map[string]string, error) {
+// buildServiceNamePrefixes returns the prefixes used to name the environment variables contributed
+// by the given service context.
+func buildServiceNamePrefixes(svcCtx *serviceContext, globalNamePrefix string) []string {
 	prefixes := []string{}
 	if len(globalNamePrefix) > 0 {
 		prefixes = append(prefixes, globalNamePrefix)
@@ -43,59 +48,109 @@ func buildServiceEnvVars(svcCtx *serviceContext, globalNamePrefix string) (map[s
 	if svcCtx.namePrefix == nil {
 		prefixes = append(prefixes, svcCtx.service.GroupVersionKind().Kind)
 	}
+	return prefixes
+}
 
-	return envvars.Build(svcCtx.envVars, prefixes...)
+// buildServiceNaming returns the naming strategy of the environment variables contributed by the
+// given service context, falling back to the one of the Service Binding.
+func buildServiceNaming(svcCtx *serviceContext, globalNamingStrategy string) (envvars.NamingStrategy, error) {
+	return envvars.ParseNamingStrategy(stringValueOrDefault(svcCtx.namingStrategy, globalNamingStrategy))
 }
 
-func (r *retriever) processServiceContext(
+func buildServiceEnvVars(
 	svcCtx *serviceContext,
-	mappingsCtx map[string]interface{},
 	globalNamePrefix string,
-) (map[string][]byte, error) {
-	svcEnvVars, err := buildServiceEnvVars(svcCtx, globalNamePrefix)
+	globalNamingStrategy string,
+) (map[string]string, error) {
+	naming, err := buildServiceNaming(svcCtx, globalNamingStrategy)
 	if err != nil {
 		return nil, err
 	}
+	return envvars.BuildWithNaming(svcCtx.envVars, naming, buildServiceNamePrefixes(svcCtx, globalNamePrefix)...)
+}
 
-	// contribute the entire resource to the context shared with the custom env parser
-	gvk := svcCtx.service.GetObjectKind().GroupVersionKind()
-
-	// add an entry in the custom environment variable context, allowing the user to use the
-	// following expression:
-	//
-	// `{{ index "v1alpha1" "postgresql.baiju.dev" "Database", "db-testing", "status", "connectionUrl" }}`
-	err = unstructured.SetNestedField(
-		mappingsCtx, svcCtx.service.Object, gvk.Version, gvk.Group, gvk.Kind,
-		svcCtx.service.GetName())
+// buildServiceBindingTypes returns the delivery medium of each environment variable contributed by
+// the given service context whose value has declared one.
+func buildServiceBindingTypes(
+	svcCtx *serviceContext,
+	globalNamePrefix string,
+	globalNamingStrategy string,
+) (map[string]binding.BindingType, error) {
+	naming, err := buildServiceNaming(svcCtx, globalNamingStrategy)
 	if err != nil {
 		return nil, err
 	}
+	prefixes := buildServiceNamePrefixes(svcCtx, globalNamePrefix)
+	bindingTypes := make(map[string]binding.BindingType)
+	for k, t := range svcCtx.bindingTypes {
+		v, ok := svcCtx.envVars[k]
+		if !ok {
+			continue
+		}
+		keyEnvVars, err := envvars.BuildWithNaming(map[string]interface{}{k: v}, naming, prefixes...)
+		if err != nil {
+			return nil, err
+		}
+		for name := range keyEnvVars {
+			bindingTypes[name] = t
+		}
+	}
+	return bindingTypes, nil
+}
+
+// splitFileValues returns a copy of the given service context without the values delivered as
+// files, and those values; values without a declared delivery medium are delivered as files when
+// bindAsFiles is true.
+func splitFileValues(svcCtx *serviceContext, bindAsFiles bool) (*serviceContext, map[string]interface{}) {
+	envCtx := *svcCtx
+	envCtx.envVars = make(map[string]interface{})
+	files := make(map[string]interface{})
+	for k, v := range svcCtx.envVars {
+		t, ok := svcCtx.bindingTypes[k]
+		if (ok && t == binding.TypeVolumeMount) || (!ok && bindAsFiles) {
+			files[k] = v
+		} else {
+			envCtx.envVars[k] = v
+		}
+	}
+	return &envCtx, files
+}
+
+func (r *retriever) processServiceContext(
+	svcCtx *serviceContext,
+	mappingsCtx map[string]interface{},
+	opts *bindingOptions,
+) (map[string][]byte, map[string]binding.BindingType, error) {
+	// values delivered as files named after their original keys are laid out by their path,
+	// while the remaining ones are named as usual
+	envCtx, files := svcCtx, map[string]interface{}{}
+	if opts.fileNaming == v1alpha1.FileNamingOriginal {
+		envCtx, files = splitFileValues(svcCtx, opts.bindAsFiles)
+	}
 
-	// add an entry in the custom environment variable context with modified key names (group
-	// names have the "." separator changed to underbar and "-" in the resource name is changed
-	// to underbar "_" as well).
-	//
-	// `{{ .v1alpha1.postgresql_baiju_dev.Database.db_testing.status.connectionUrl }}`
-	err = unstructured.SetNestedField(
-		mappingsCtx,
-		svcCtx.service.Object,
-		createServiceIndexPath(svcCtx.service.GetName(), svcCtx.service.GroupVersionKind())...,
-	)
+	svcEnvVars, err := buildServiceEnvVars(envCtx, opts.namePrefix, opts.namingStrategy)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	// add an entry in the custom environment variable context with the informed 'id'.
-	//
-	// `{{ .db_testing.status.connectionUrl }}`
-	if svcCtx.id != nil {
-		err = unstructured.SetNestedField(
-			mappingsCtx,
-			svcCtx.service.Object,
-			*svcCtx.id,
-		)
-		if err != nil {
-			return nil, err
+	svcBindingTypes, err := buildServiceBindingTypes(envCtx, opts.namePrefix, opts.namingStrategy)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fileEnvVars, err := envvars.BuildWithNaming(files, envvars.FilePathNaming)
+	if err != nil {
+		return nil, nil, err
+	}
+	for k, v := range fileEnvVars {
+		svcEnvVars[k] = v
+		svcBindingTypes[k] = binding.TypeVolumeMount
+	}
+
+	// contribute the entire resource to the context shared with the custom env parser
+	for _, p := range mappingContextPaths(svcCtx) {
+		if err := unstructured.SetNestedField(mappingsCtx, svcCtx.service.Object, p...); err != nil {
+			return nil, nil, err
 		}
 	}
 
@@ -104,41 +159,210 @@ func (r *retriever) processServiceContext(
 		envVars[k] = []byte(v)
 	}
 
-	return envVars, nil
+	return envVars, svcBindingTypes, nil
+}
+
+// bindingOptions holds the settings of the Service Binding the binding values are built with.
+type bindingOptions struct {
+	// namePrefix is prepended to the names of the values collected from services.
+	namePrefix string
+	// namingStrategy names the values collected from services lacking their own.
+	namingStrategy string
+	// collisionPolicy resolves the keys provided by more than one service or mapping.
+	collisionPolicy v1alpha1.KeyCollisionPolicy
+	// mappings are the custom binding values.
+	mappings []v1alpha1.Mapping
+	// files are the files aggregating all the binding values.
+	files []v1alpha1.BindingFile
+	// flavors are the frameworks whose conventions the values collected from services are
+	// translated to.
+	flavors []string
+	// bindAsFiles is true when the binding is delivered as files by default.
+	bindAsFiles bool
+	// arrays configures how the arrays of values collected from services lacking their own
+	// configuration are rendered.
+	arrays envvars.ArrayRendering
+	// fileNaming configures how the values collected from services delivered as files are named.
+	fileNaming v1alpha1.FileNaming
 }
 
-// ProcessServiceContexts returns environment variables and volume keys from a ServiceContext slice.
+// newBindingOptions returns the binding options configured in the given Service Binding.
+func newBindingOptions(sbr *v1alpha1.ServiceBinding) *bindingOptions {
+	return &bindingOptions{
+		namePrefix:      sbr.Spec.NamePrefix,
+		namingStrategy:  sbr.Spec.NamingStrategy,
+		collisionPolicy: sbr.Spec.KeyCollisionPolicy,
+		mappings:        sbr.Spec.Mappings,
+		files:           sbr.Spec.BindingFiles,
+		flavors:         sbr.Spec.Flavors,
+		bindAsFiles:     sbr.Spec.BindAsFiles,
+		arrays: envvars.ArrayRendering{
+			Format:    envvars.ArrayFormat(sbr.Spec.ArrayFormat),
+			Separator: sbr.Spec.ArraySeparator,
+		},
+		fileNaming: sbr.Spec.FileNaming,
+	}
+}
+
+// ProcessServiceContexts returns environment variables and volume keys from a ServiceContext slice,
+// together with the delivery medium declared for the keys that have one. The keys collected from
+// each service are selected and renamed as declared by its selector. Keys provided by more
+// than one service or mapping are resolved according to the collision policy, and reported in the
+// returned binding.
 func (r *retriever) ProcessServiceContexts(
-	globalNamePrefix string,
 	svcCtxs serviceContextList,
-	envVarTemplates []v1alpha1.Mapping,
-) (map[string][]byte, error) {
+	opts *bindingOptions,
+) (*internalBinding, error) {
 	mappingsCtx := make(map[string]interface{})
-	envVars := make(map[string][]byte)
+	keys := newBindingKeys(opts.bindAsFiles)
 
 	for _, svcCtx := range svcCtxs {
-		s, err := r.processServiceContext(svcCtx, mappingsCtx, globalNamePrefix)
+		rendered, err := renderServiceArrays(svcCtx, opts.arrays)
 		if err != nil {
 			return nil, err
 		}
-		for k, v := range s {
-			envVars[k] = []byte(v)
+		s, bindingTypes, err := r.processServiceContext(rendered, mappingsCtx, opts)
+		if err != nil {
+			return nil, err
+		}
+		s, bindingTypes, origins, err := selectServiceKeys(r.logger, svcCtx, s, bindingTypes)
+		if err != nil {
+			return nil, err
+		}
+		var docs map[string]envvars.Leaf
+		if len(opts.files) > 0 {
+			if docs, err = serviceDocumentEntries(rendered, origins, opts); err != nil {
+				return nil, err
+			}
+		}
+		if err = keys.addService(svcCtx, s, bindingTypes, docs); err != nil {
+			return nil, err
+		}
+		if err = addFlavors(keys, svcCtx, opts); err != nil {
+			return nil, err
 		}
 	}
 
-	envParser := newMappingsParser(envVarTemplates, mappingsCtx)
+	envParser := newMappingsParser(opts.mappings, mappingsCtx, keys)
 	mappingsList, err := envParser.Parse()
 	if err != nil {
-		r.logger.Error(
-			err, "Creating envVars", "Templates", envVarTemplates, "TemplateContext", mappingsCtx)
+		r.logger.Error(err, "Creating envVars", "Templates", opts.mappings)
+		return nil, err
+	}
+	if err = keys.addMappings(opts.mappings, mappingsList); err != nil {
+		return nil, err
+	}
+
+	b, err := keys.resolve(opts.collisionPolicy)
+	if err != nil {
+		return nil, err
+	}
+	if err = addBindingFiles(b, opts.files); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// serviceDocumentEntries returns the entries of the aggregated binding files for the keys published
+// by the given service context, whose origins are the keys they have been collected as: values are
+// nested under the service prefixes at their path, keeping their type, while renamed keys are
+// nested under the service prefixes as renamed.
+func serviceDocumentEntries(
+	svcCtx *serviceContext,
+	origins map[string]string,
+	opts *bindingOptions,
+) (map[string]envvars.Leaf, error) {
+	envCtx, files := svcCtx, map[string]interface{}{}
+	if opts.fileNaming == v1alpha1.FileNamingOriginal {
+		envCtx, files = splitFileValues(svcCtx, opts.bindAsFiles)
+	}
+	naming, err := buildServiceNaming(envCtx, opts.namingStrategy)
+	if err != nil {
+		return nil, err
+	}
+	prefixes := buildServiceNamePrefixes(envCtx, opts.namePrefix)
+	leaves, err := envvars.BuildLeaves(envCtx.envVars, naming, prefixes...)
+	if err != nil {
+		return nil, err
+	}
+	fileLeaves, err := envvars.BuildLeaves(files, envvars.FilePathNaming)
+	if err != nil {
 		return nil, err
 	}
+	for k, l := range fileLeaves {
+		leaves[k] = l
+	}
 
-	for k, v := range mappingsList {
-		envVars[k] = []byte(v.(string))
+	var docPrefixes []string
+	for _, p := range prefixes {
+		if len(p) > 0 {
+			docPrefixes = append(docPrefixes, p)
+		}
+	}
+	docs := make(map[string]envvars.Leaf, len(origins))
+	for key, origin := range origins {
+		l, ok := leaves[origin]
+		if !ok {
+			continue
+		}
+		p := l.Path
+		if key != origin {
+			p = []string{key}
+		}
+		docs[key] = envvars.Leaf{Path: append(append([]string{}, docPrefixes...), p...), Value: l.Value}
 	}
+	return docs, nil
+}
 
-	return envVars, nil
+// renderServiceArrays returns a copy of the given service context whose values have their arrays
+// rendered as declared by the binding annotations, or by the given default; values are delivered
+// the same way whether as environment variables or as files.
+func renderServiceArrays(svcCtx *serviceContext, defaultArrays envvars.ArrayRendering) (*serviceContext, error) {
+	rendered := *svcCtx
+	rendered.envVars = make(map[string]interface{}, len(svcCtx.envVars))
+	for k, v := range svcCtx.envVars {
+		arrays, ok := svcCtx.arrays[k]
+		if !ok {
+			arrays = defaultArrays
+		}
+		r, err := envvars.RenderArrays(v, arrays)
+		if err != nil {
+			return nil, fmt.Errorf("rendering arrays of %q in %s: %v", k, describeService(svcCtx), err)
+		}
+		rendered.envVars[k] = r
+	}
+	return &rendered, nil
+}
+
+// addFlavors adds the entries the values of the given service context are translated to by the
+// flavors of the Service Binding.
+func addFlavors(keys *bindingKeys, svcCtx *serviceContext, opts *bindingOptions) error {
+	if len(opts.flavors) == 0 {
+		return nil
+	}
+	svc := flavor.NewService(svcCtx.envVars)
+	for _, name := range opts.flavors {
+		t, err := flavor.DefaultRegistry.Lookup(name)
+		if err != nil {
+			return err
+		}
+		entries, err := t.Translate(svc)
+		if err != nil {
+			return fmt.Errorf("%s flavor of %s: %v", name, describeService(svcCtx), err)
+		}
+		envVars := make(map[string][]byte, len(entries))
+		for k, v := range entries {
+			if !opts.bindAsFiles {
+				k = flavor.EnvVarName(k)
+			}
+			envVars[k] = []byte(v)
+		}
+		source := fmt.Sprintf("%s flavor of %s", name, describeService(svcCtx))
+		if err := keys.add(source, serviceSuffix(svcCtx), envVars, nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // NewRetriever instantiate a new retriever instance.
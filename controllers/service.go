@@ -60,19 +60,62 @@ func findService(
 // crdGVR is the plural GVR for Kubernetes CRDs.
 var crdGVR = schema.GroupVersionResource{
 	Group:    "apiextensions.k8s.io",
-	Version:  "v1beta1",
+	Version:  "v1",
 	Resource: "customresourcedefinitions",
 }
 
-func findServiceCRD(client dynamic.Interface, gvk schema.GroupVersionKind) (*unstructured.Unstructured, error) {
-	// gvr is the plural guessed resource for the given GVK
-	gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+func findServiceCRD(
+	client dynamic.Interface,
+	gvk schema.GroupVersionKind,
+	restMapper meta.RESTMapper,
+) (*unstructured.Unstructured, error) {
+	// mapping contains the plural resource served by the API server for the given GVK
+	mapping, err := restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		// a kind unknown to the API server can't have a CRD either
+		return nil, k8serrors.NewNotFound(crdGVR.GroupResource(), gvk.GroupKind().String())
+	} else if err != nil {
+		return nil, err
+	}
 	// crdName is the string'fied GroupResource, e.g. "deployments.apps"
-	crdName := gvr.GroupResource().String()
+	crdName := mapping.Resource.GroupResource().String()
 	// delegate the search to the CustomResourceDefinition resource client
 	return client.Resource(crdGVR).Get(context.TODO(), crdName, metav1.GetOptions{})
 }
 
+// crdBindingAnnotations returns the annotations of the given CRD applying to the informed version.
+// Binding annotations can be scoped to a served version by prefixing them with the version name,
+// for example "v1alpha1.service.binding/username" or "v1alpha1.service.binding.json/username";
+// those override the unscoped annotations with the same name and are ignored for other versions.
+// Descriptors annotations, such as "v1alpha1.service.binding.descriptors/status.dbCredentials",
+// and the binding provider annotation, such as "v1alpha1.service.binding.provider", are scoped the
+// same way.
+func crdBindingAnnotations(crd *unstructured.Unstructured, version string) map[string]string {
+	anns := make(map[string]string)
+	scoped := make(map[string]string)
+
+	for k, v := range crd.GetAnnotations() {
+		// version names can't contain dots, so whatever follows the first one is the unscoped name
+		p := strings.SplitN(k, ".", 2)
+		switch {
+		case len(p) < 2 || !(binding.IsBindingAnnotation(p[1]) || p[1] == binding.ProviderAnnotation ||
+			strings.HasPrefix(p[1], descriptorsAnnotationPrefix)):
+			anns[k] = v
+		case p[0] == version:
+			scoped[p[1]] = v
+		default:
+			// scoped to another version
+			continue
+		}
+	}
+
+	for k, v := range scoped {
+		anns[k] = v
+	}
+
+	return anns
+}
+
 func loadDescriptor(anns map[string]string, path string, descriptor string, root string, objectType string) {
 	if !strings.HasPrefix(descriptor, binding.AnnotationPrefix) {
 		return
@@ -227,11 +270,17 @@ func buildOwnedResourceContext(
 		ownerNamePrefix,
 		restMapper,
 		nil,
+		nil,
+		"",
+		nil,
 	)
 	if err != nil {
 		return nil, err
 	}
 	svcCtx.envVars, _, err = nested.GetValue(obj.Object, inputPath, outputPath)
+	// values extracted from owned resources are always delivered as configured in the Service
+	// Binding
+	svcCtx.bindingTypes = nil
 	return svcCtx, err
 }
 
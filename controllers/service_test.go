@@ -8,6 +8,9 @@ import (
 	"github.com/redhat-developer/service-binding-operator/pkg/testutils"
 	"github.com/redhat-developer/service-binding-operator/test/mocks"
 	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func init() {
@@ -89,11 +92,69 @@ func TestFindServiceCRD(t *testing.T) {
 	cr := f.AddMockedDatabaseCR("database", ns)
 
 	t.Run("golden path", func(t *testing.T) {
-		crd, err := findServiceCRD(f.FakeDynClient(), cr.GetObjectKind().GroupVersionKind())
+		crd, err := findServiceCRD(f.FakeDynClient(), cr.GetObjectKind().GroupVersionKind(), testutils.BuildTestRESTMapper())
 		require.NoError(t, err)
 		require.NotNil(t, crd)
 		require.Equal(t, expected, crd)
 	})
+
+	t.Run("kind unknown to the API server", func(t *testing.T) {
+		crd, err := findServiceCRD(f.FakeDynClient(), schema.GroupVersionKind{
+			Group:   "example.com",
+			Version: "v1",
+			Kind:    "Unknown",
+		}, testutils.BuildTestRESTMapper())
+		require.Error(t, err)
+		require.True(t, k8serrors.IsNotFound(err))
+		require.Nil(t, crd)
+	})
+}
+
+func TestCRDBindingAnnotations(t *testing.T) {
+	crd := &unstructured.Unstructured{}
+	crd.SetAnnotations(map[string]string{
+		"service.binding/username":                                 "path={.status.username}",
+		"service.binding/password":                                 "path={.status.password}",
+		"v1beta1.service.binding/password":                         "path={.status.credentials.password}",
+		"v1beta1.service.binding/host":                             "path={.status.address.host}",
+		"v1alpha1.service.binding/host":                            "path={.status.host}",
+		"v1alpha1.service.binding":                                 "path={.status.secret},objectType=Secret",
+		"example.com/unrelated":                                    "value",
+		"v1beta1.service.binding.other/key":                        "value",
+		"service.binding.json/port":                                `{"path": "{.status.port}"}`,
+		"v1beta1.service.binding.json/port":                        `{"path": "{.status.address.port}"}`,
+		"service.binding.descriptors/status.dbCredentials":         "urn:alm:descriptor:io.kubernetes:Secret",
+		"v1beta1.service.binding.descriptors/status.dbCredentials": "urn:alm:descriptor:io.kubernetes:ConfigMap",
+		"service.binding.provider":                                 "https://provider.example.svc/db",
+		"v1beta1.service.binding.provider":                         "https://provider.example.svc/db-v1beta1",
+	})
+
+	t.Run("unscoped and v1alpha1 annotations", func(t *testing.T) {
+		require.Equal(t, map[string]string{
+			"service.binding/username":                         "path={.status.username}",
+			"service.binding/password":                         "path={.status.password}",
+			"service.binding/host":                             "path={.status.host}",
+			"service.binding":                                  "path={.status.secret},objectType=Secret",
+			"example.com/unrelated":                            "value",
+			"v1beta1.service.binding.other/key":                "value",
+			"service.binding.json/port":                        `{"path": "{.status.port}"}`,
+			"service.binding.descriptors/status.dbCredentials": "urn:alm:descriptor:io.kubernetes:Secret",
+			"service.binding.provider":                         "https://provider.example.svc/db",
+		}, crdBindingAnnotations(crd, "v1alpha1"))
+	})
+
+	t.Run("v1beta1 annotations override unscoped ones", func(t *testing.T) {
+		require.Equal(t, map[string]string{
+			"service.binding/username":                         "path={.status.username}",
+			"service.binding/password":                         "path={.status.credentials.password}",
+			"service.binding/host":                             "path={.status.address.host}",
+			"example.com/unrelated":                            "value",
+			"v1beta1.service.binding.other/key":                "value",
+			"service.binding.json/port":                        `{"path": "{.status.address.port}"}`,
+			"service.binding.descriptors/status.dbCredentials": "urn:alm:descriptor:io.kubernetes:ConfigMap",
+			"service.binding.provider":                         "https://provider.example.svc/db-v1beta1",
+		}, crdBindingAnnotations(crd, "v1beta1"))
+	})
 }
 
 func TestGetObjectType(t *testing.T) {
@@ -15,7 +15,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/redhat-developer/service-binding-operator/api/v1alpha1"
+	"github.com/redhat-developer/service-binding-operator/pkg/binding"
 	"github.com/redhat-developer/service-binding-operator/pkg/converter"
+	"github.com/redhat-developer/service-binding-operator/pkg/envvars"
 	"github.com/redhat-developer/service-binding-operator/pkg/log"
 )
 
@@ -341,6 +343,7 @@ func (b *serviceBinder) bind() (reconcile.Result, error) {
 		return b.onError(err, b.sbr, sbrStatus, nil)
 	}
 	b.setApplicationObjects(sbrStatus, updatedObjects)
+	sbrStatus.InjectedEnvVars = b.binder.injectedEnvVars()
 
 	meta.SetStatusCondition(&sbrStatus.Conditions, metav1.Condition{
 		Type:   v1alpha1.InjectionReady,
@@ -441,6 +444,9 @@ func buildServiceBinder(
 		options.sbr,
 		options.restMapper,
 	)
+	binder.bindingTypes = options.binding.bindingTypes
+	binder.filePaths = options.binding.filePaths
+	binder.fileModes = options.binding.fileModes
 
 	ensureDefaults(options.sbr.Spec.Application)
 
@@ -457,21 +463,29 @@ func buildServiceBinder(
 
 type internalBinding struct {
 	envVars map[string][]byte
+	// bindingTypes contains the delivery medium for envVars keys declaring one.
+	bindingTypes map[string]binding.BindingType
+	// filePaths contains the path of the files holding envVars keys delivered as files, for the
+	// keys whose file isn't named after them.
+	filePaths map[string]string
+	// fileModes contains the mode of the files holding envVars keys delivered as files, for the
+	// keys declaring one.
+	fileModes map[string]int32
+	// docs contains the location and value of envVars keys in the aggregated binding files, for
+	// the keys part of those.
+	docs map[string]envvars.Leaf
+	// fileMappings contains the envVars keys holding the files rendered by mappings.
+	fileMappings map[string]bool
+	// collisions contains the keys provided by more than one source, and how they were resolved.
+	collisions []v1alpha1.KeyCollision
+	// sanitizedKeys contains the keys renamed, or dropped, because they weren't valid.
+	sanitizedKeys []v1alpha1.SanitizedKey
 }
 
 func buildBinding(
 	client dynamic.Interface,
-	mappings []v1alpha1.Mapping,
 	svcCtxs serviceContextList,
-	globalNamePrefix string,
+	opts *bindingOptions,
 ) (*internalBinding, error) {
-	envVars, err := NewRetriever(client).
-		ProcessServiceContexts(globalNamePrefix, svcCtxs, mappings)
-	if err != nil {
-		return nil, err
-	}
-
-	return &internalBinding{
-		envVars: envVars,
-	}, nil
+	return NewRetriever(client).ProcessServiceContexts(svcCtxs, opts)
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package controllers
 
 import (
+	"github.com/redhat-developer/service-binding-operator/pkg/binding"
 	"github.com/redhat-developer/service-binding-operator/pkg/log"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -40,6 +41,7 @@ type ServiceBindingReconciler struct {
 	dynClient       dynamic.Interface // kubernetes dynamic api client
 	resourceWatcher ResourceWatcher   // ResourceWatcher to add watching for specific GVK/GVR
 	restMapper      meta.RESTMapper
+	bindingProvider binding.BindingProvider // provider computing binding values for annotated services
 }
 
 // +kubebuilder:rbac:groups=operators.coreos.com,resources=servicebindings,verbs=get;list;watch;create;update;patch;delete
@@ -78,6 +80,9 @@ func (r *ServiceBindingReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	r.dynClient = client
 	r.restMapper = mgr.GetRESTMapper()
+	if r.bindingProvider, err = newBindingProvider(); err != nil {
+		return err
+	}
 	sbr := &sbrController{
 		Controller:   c,
 		Client:       client,
@@ -1,11 +1,16 @@
 package controllers
 
 import (
+	goerrors "errors"
+	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/imdario/mergo"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -13,6 +18,7 @@ import (
 
 	v1alpha1 "github.com/redhat-developer/service-binding-operator/api/v1alpha1"
 	"github.com/redhat-developer/service-binding-operator/pkg/binding"
+	"github.com/redhat-developer/service-binding-operator/pkg/envvars"
 	"github.com/redhat-developer/service-binding-operator/pkg/log"
 )
 
@@ -22,10 +28,86 @@ type serviceContext struct {
 	service *unstructured.Unstructured
 	// envVars contains the service's contributed environment variables.
 	envVars map[string]interface{}
+	// bindingTypes contains the delivery medium declared for envVars' top-level keys; keys absent
+	// from it are delivered as configured in the Service Binding.
+	bindingTypes map[string]binding.BindingType
+	// arrays contains the rendering of arrays declared for envVars' top-level keys; keys absent from
+	// it are rendered as configured in the Service Binding.
+	arrays map[string]envvars.ArrayRendering
 	// namePrefix indicates the prefix to use in environment variables.
 	namePrefix *string
 	// Id indicates a name the service can be referred in custom environment variables.
 	id *string
+	// inferredAnnotations contains the binding annotations detected for a service without binding
+	// metadata.
+	inferredAnnotations map[string]string
+	// namingStrategy overrides the naming strategy of the Service Binding for envVars.
+	namingStrategy *string
+	// include, exclude and rename select and rename the keys envVars are published as.
+	include []string
+	exclude []string
+	rename  []v1alpha1.KeyRename
+}
+
+// setKeySelection sets the naming strategy and the key selection declared in the given service
+// selector.
+func (s *serviceContext) setKeySelection(selector v1alpha1.Service) {
+	s.namingStrategy = selector.NamingStrategy
+	s.include = selector.Include
+	s.exclude = selector.Exclude
+	s.rename = selector.Rename
+}
+
+// errRequiredValuesNotFound is returned when values declared as required by the binding
+// annotations of a service can't be found.
+type errRequiredValuesNotFound struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+	name      string
+	paths     []string
+}
+
+func (e errRequiredValuesNotFound) Error() string {
+	return fmt.Sprintf("service %s %s/%s is missing required binding values at %s",
+		e.gvk.Kind, e.namespace, e.name, strings.Join(e.paths, ", "))
+}
+
+// errBindingDefinitionsFailed is returned when binding annotations of a service are invalid, or
+// fail collecting their values, e.g. a malformed annotation or a failing script.
+type errBindingDefinitionsFailed struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+	name      string
+	// failures contains the failure of each annotation, by annotation name.
+	failures map[string]error
+}
+
+func (e errBindingDefinitionsFailed) Error() string {
+	names := make([]string, 0, len(e.failures))
+	for n := range e.failures {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	msgs := make([]string, 0, len(names))
+	for _, n := range names {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", n, e.failures[n]))
+	}
+	return fmt.Sprintf("service %s %s/%s has failing binding annotations: %s",
+		e.gvk.Kind, e.namespace, e.name, strings.Join(msgs, "; "))
+}
+
+// errCredentialProfileNotFound is returned when the credential profile selected for a service
+// hasn't been declared by it.
+type errCredentialProfileNotFound struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+	name      string
+	profile   string
+}
+
+func (e errCredentialProfileNotFound) Error() string {
+	return fmt.Sprintf("service %s %s/%s does not declare credential profile %q",
+		e.gvk.Kind, e.namespace, e.name, e.profile)
 }
 
 // serviceContextList is a list of ServiceContext values.
@@ -40,6 +122,25 @@ func (sc serviceContextList) getServices() []*unstructured.Unstructured {
 	return crs
 }
 
+// getInferredBindings returns the binding annotations detected for the services in the
+// collection.
+func (sc serviceContextList) getInferredBindings() []v1alpha1.InferredBinding {
+	var inferred []v1alpha1.InferredBinding
+	for _, s := range sc {
+		if len(s.inferredAnnotations) == 0 {
+			continue
+		}
+		gvk := s.service.GroupVersionKind()
+		inferred = append(inferred, v1alpha1.InferredBinding{
+			GroupVersionKind: metav1.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind},
+			Namespace:        s.service.GetNamespace(),
+			Name:             s.service.GetName(),
+			Annotations:      s.inferredAnnotations,
+		})
+	}
+	return inferred
+}
+
 func stringValueOrDefault(val *string, defaultVal string) string {
 	if val != nil && len(*val) > 0 {
 		return *val
@@ -55,6 +156,8 @@ func buildServiceContexts(
 	defaultNs string,
 	selectors []v1alpha1.Service,
 	includeServiceOwnedResources *bool,
+	detectBindingFields v1alpha1.DetectionMode,
+	providerCtx *bindingProviderContext,
 	restMapper meta.RESTMapper,
 ) (serviceContextList, error) {
 	svcCtxs := make(serviceContextList, 0)
@@ -64,7 +167,7 @@ SELECTORS:
 		ns := stringValueOrDefault(s.Namespace, defaultNs)
 		gvk := schema.GroupVersionKind{Kind: s.Kind, Version: s.Version, Group: s.Group}
 		svcCtx, err := buildServiceContext(logger.WithName("buildServiceContexts"), client, ns, gvk,
-			s.Name, s.NamePrefix, restMapper, s.Id)
+			s.Name, s.NamePrefix, restMapper, s.Id, s.Profile, detectBindingFields, providerCtx)
 
 		if err != nil {
 			// best effort approach; should not break in common cases such as a unknown annotation
@@ -76,6 +179,7 @@ SELECTORS:
 			}
 			return nil, err
 		}
+		svcCtx.setKeySelection(s)
 		svcCtxs = append(svcCtxs, svcCtx)
 
 		if includeServiceOwnedResources != nil && *includeServiceOwnedResources {
@@ -97,6 +201,9 @@ SELECTORS:
 			if err != nil {
 				return nil, err
 			}
+			for _, ownedCtx := range ownedResourcesCtxs {
+				ownedCtx.setKeySelection(s)
+			}
 			svcCtxs = append(svcCtxs, ownedResourcesCtxs...)
 		}
 	}
@@ -157,9 +264,11 @@ func runHandler(
 	key string,
 	value string,
 	envVars map[string]interface{},
+	bindingTypes map[string]binding.BindingType,
+	arrays map[string]envvars.ArrayRendering,
 	restMapper meta.RESTMapper,
 ) error {
-	h, err := binding.NewSpecHandler(client, key, value, *obj, restMapper)
+	h, err := binding.NewSpecHandler(client, key, value, *obj, restMapper, binding.DefaultDefinitionRegistry)
 	if err != nil {
 		return err
 	}
@@ -179,9 +288,75 @@ func runHandler(
 		return err
 	}
 
+	if len(r.Type) > 0 {
+		for k := range r.Data {
+			bindingTypes[k] = r.Type
+		}
+	}
+
+	if len(r.Arrays.Format) > 0 {
+		for k := range r.Data {
+			arrays[k] = r.Arrays
+		}
+	}
+
 	return nil
 }
 
+// collectCRDAnnotations collects the binding annotations declared for the informed version of the
+// given CRD, in increasing order of precedence: the CRDDescription descriptors (if any) and the
+// CRD annotations.
+func collectCRDAnnotations(
+	crd *unstructured.Unstructured,
+	version string,
+	crdDescription *olmv1alpha1.CRDDescription,
+) (map[string]string, error) {
+	anns := map[string]string{}
+
+	// start with annotations extracted from CRDDescription
+	if crdDescription != nil {
+		err := mergo.Merge(
+			&anns, convertCRDDescriptionToAnnotations(crdDescription), mergo.WithOverride)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// then override collected annotations with CRD annotations applying to the informed version
+	err := mergo.Merge(&anns, crdBindingAnnotations(crd, version), mergo.WithOverride)
+	if err != nil {
+		return nil, err
+	}
+
+	return anns, nil
+}
+
+// hasBindingAnnotations evaluates whether any of the given annotations declares a binding or a
+// binding provider.
+func hasBindingAnnotations(anns map[string]string) bool {
+	for k := range anns {
+		if binding.IsBindingAnnotation(k) || k == binding.ProviderAnnotation {
+			return true
+		}
+	}
+	return false
+}
+
+// withProfileAnnotations adds the annotations of the binding.Profile registered for the given kind
+// when no binding annotations have been collected.
+func withProfileAnnotations(anns map[string]string, gvk schema.GroupVersionKind) map[string]string {
+	if hasBindingAnnotations(anns) {
+		return anns
+	}
+	profileAnns := binding.DefaultProfileRegistry.Annotations(gvk)
+	if len(profileAnns) == 0 {
+		return anns
+	}
+	for k, v := range anns {
+		profileAnns[k] = v
+	}
+	return profileAnns
+}
+
 // buildServiceContext inspects g the API server searching for the service resources, associated CRD
 // and OLM's CRDDescription if present, and processes those with relevant annotations to compose a
 // ServiceContext.
@@ -194,6 +369,9 @@ func buildServiceContext(
 	namePrefix *string,
 	restMapper meta.RESTMapper,
 	id *string,
+	profile *string,
+	detectBindingFields v1alpha1.DetectionMode,
+	providerCtx *bindingProviderContext,
 ) (*serviceContext, error) {
 	obj, err := findService(client, ns, gvk, name)
 	if err != nil {
@@ -204,7 +382,7 @@ func buildServiceContext(
 
 	// attempt to search the CRD of given gvk and bail out right away if a CRD can't be found; this
 	// means also a CRDDescription can't exist or if it does exist it is not meaningful.
-	crd, err := findServiceCRD(client, gvk)
+	crd, err := findServiceCRD(client, gvk, restMapper)
 	if err != nil && !errors.IsNotFound(err) {
 		return nil, err
 	} else if !errors.IsNotFound(err) {
@@ -213,17 +391,11 @@ func buildServiceContext(
 		if err != nil && !errors.IsNotFound(err) {
 			return nil, err
 		}
-		// start with annotations extracted from CRDDescription
-		err = mergo.Merge(
-			&anns, convertCRDDescriptionToAnnotations(crdDescription), mergo.WithOverride)
-		if err != nil {
-			return nil, err
-		}
-		// then override collected annotations with CRD annotations
-		err = mergo.Merge(&anns, crd.GetAnnotations(), mergo.WithOverride)
+		crdAnns, err := collectCRDAnnotations(crd, gvk.Version, crdDescription)
 		if err != nil {
 			return nil, err
 		}
+		anns = crdAnns
 	}
 
 	// and finally override collected annotations with own annotations
@@ -231,8 +403,44 @@ func buildServiceContext(
 	if err != nil {
 		return nil, err
 	}
+	anns = withProfileAnnotations(anns, gvk)
+
+	// as a last resort, look for well-known fields in the service when requested
+	var inferredAnns map[string]string
+	if len(detectBindingFields) > 0 && !hasBindingAnnotations(anns) {
+		inferredAnns, err = binding.DetectAnnotations(client, obj)
+		if err != nil {
+			return nil, err
+		}
+		if detectBindingFields == v1alpha1.DetectionModeApply {
+			for k, v := range inferredAnns {
+				anns[k] = v
+			}
+		}
+	}
+
+	// keep only the binding annotations shared by all credential profiles and the ones of the
+	// selected profile
+	anns, err = binding.SelectCredentialProfile(anns, stringValueOrDefault(profile, ""))
+	if binding.IsErrCredentialProfileNotFound(err) {
+		return nil, errCredentialProfileNotFound{
+			gvk:       gvk,
+			namespace: ns,
+			name:      name,
+			profile:   *profile,
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	// the binding provider isn't a binding definition, and is only called once the values declared
+	// by the service have been collected
+	providerEndpoint, hasProvider := anns[binding.ProviderAnnotation]
+	delete(anns, binding.ProviderAnnotation)
 
 	envVars := make(map[string]interface{})
+	bindingTypes := make(map[string]binding.BindingType)
+	arrays := make(map[string]envvars.ArrayRendering)
 
 	// outputObj will be used to keep the changes processed by the handler.
 	outputObj := obj.DeepCopy()
@@ -244,20 +452,68 @@ func buildServiceContext(
 
 	sort.Strings(keys)
 
+	var missingPaths []string
+	failures := make(map[string]error)
 	for _, k := range keys {
+		// annotations unrelated to bindings are left alone
+		if !binding.IsBindingAnnotation(k) {
+			continue
+		}
 		v := anns[k]
-		// runHandler modifies 'outputObj', and 'envVars' in place.
-		err := runHandler(client, obj, outputObj, k, v, envVars, restMapper)
+		// runHandler modifies 'outputObj', 'envVars', 'bindingTypes' and 'arrays' in place.
+		err := runHandler(client, obj, outputObj, k, v, envVars, bindingTypes, arrays, restMapper)
+		var requiredErr binding.ErrRequiredValueNotFound
+		if goerrors.As(err, &requiredErr) {
+			missingPaths = append(missingPaths, requiredErr.Path)
+		} else if err != nil {
+			failures[k] = err
+		}
+	}
+
+	// values silently missing from the binding would only be noticed by the application
+	if len(failures) > 0 {
+		return nil, errBindingDefinitionsFailed{
+			gvk:       gvk,
+			namespace: ns,
+			name:      name,
+			failures:  failures,
+		}
+	}
+	if len(missingPaths) > 0 {
+		return nil, errRequiredValuesNotFound{
+			gvk:       gvk,
+			namespace: ns,
+			name:      name,
+			paths:     missingPaths,
+		}
+	}
+
+	// values returned by the binding provider take precedence over the ones declared by the service
+	if hasProvider && providerCtx != nil {
+		values, err := providerCtx.provide(providerEndpoint, obj, id)
 		if err != nil {
-			logger.Debug("Failed executing runHandler", "Error", err)
+			return nil, errBindingProviderFailed{
+				gvk:       gvk,
+				namespace: ns,
+				name:      name,
+				err:       err,
+			}
+		}
+		for k, v := range values {
+			envVars[k] = v
+			delete(bindingTypes, k)
+			delete(arrays, k)
 		}
 	}
 
 	serviceCtx := &serviceContext{
-		service:    outputObj,
-		envVars:    envVars,
-		namePrefix: namePrefix,
-		id:         id,
+		service:             outputObj,
+		envVars:             envVars,
+		bindingTypes:        bindingTypes,
+		arrays:              arrays,
+		namePrefix:          namePrefix,
+		id:                  id,
+		inferredAnnotations: inferredAnns,
 	}
 
 	return serviceCtx, nil
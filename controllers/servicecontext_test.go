@@ -1,18 +1,26 @@
 package controllers
 
 import (
-	"github.com/redhat-developer/service-binding-operator/pkg/converter"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/redhat-developer/service-binding-operator/pkg/converter"
+
 	corev1 "k8s.io/api/core/v1"
+	apiextensionv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 
 	"github.com/redhat-developer/service-binding-operator/api/v1alpha1"
+	"github.com/redhat-developer/service-binding-operator/pkg/binding"
 	"github.com/redhat-developer/service-binding-operator/pkg/log"
 	"github.com/redhat-developer/service-binding-operator/pkg/testutils"
 	"github.com/redhat-developer/service-binding-operator/test/mocks"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func TestBuildServiceContexts(t *testing.T) {
@@ -24,7 +32,7 @@ func TestBuildServiceContexts(t *testing.T) {
 		ns := "planner"
 		f := mocks.NewFake(t, ns)
 		serviceCtxs, err := buildServiceContexts(
-			logger, f.FakeDynClient(), ns, nil, &falseBool, restMapper)
+			logger, f.FakeDynClient(), ns, nil, &falseBool, "", nil, restMapper)
 
 		require.NoError(t, err, "buildServiceContexts must execute without errors")
 		require.Empty(t, serviceCtxs, "buildServiceContexts must be empty")
@@ -48,7 +56,7 @@ func TestBuildServiceContexts(t *testing.T) {
 		sbr := f.AddMockedServiceBinding(sbrName, nil, firstResourceRef, "", deploymentsGVR, matchLabels)
 
 		serviceCtxs, err := buildServiceContexts(
-			logger, f.FakeDynClient(), firstNamespace, sbr.Spec.Services, &falseBool, restMapper)
+			logger, f.FakeDynClient(), firstNamespace, sbr.Spec.Services, &falseBool, "", nil, restMapper)
 
 		require.NoError(t, err, "buildServiceContexts must execute without errors")
 		require.Len(t, serviceCtxs, 1, "buildServiceContexts must return only one item")
@@ -119,7 +127,7 @@ func TestBuildServiceContexts(t *testing.T) {
 		}
 
 		serviceCtxs, err := buildServiceContexts(
-			logger, f.FakeDynClient(), sameNs, sbr.Spec.Services, &falseBool, restMapper)
+			logger, f.FakeDynClient(), sameNs, sbr.Spec.Services, &falseBool, "", nil, restMapper)
 
 		require.NoError(t, err, "buildServiceContexts must execute without errors")
 		require.Len(t, serviceCtxs, 2, "buildServiceContexts must return both service contexts")
@@ -154,6 +162,409 @@ func TestBuildServiceContexts(t *testing.T) {
 	})
 }
 
+// databaseService configures the Database service, and its fake cluster, the service contexts are
+// built from by buildDatabaseServiceContexts.
+type databaseService struct {
+	// annotations are the annotations of the service.
+	annotations map[string]string
+	// secrets are the Secrets added to the fake cluster, by name.
+	secrets map[string]map[string][]byte
+	// status are the fields set in the status of the service.
+	status map[string]interface{}
+	// profile and id are set in the service selector.
+	profile *string
+	id      *string
+	// detectionMode and providerCtx are passed to buildServiceContexts.
+	detectionMode v1alpha1.DetectionMode
+	providerCtx   *bindingProviderContext
+}
+
+// buildDatabaseServiceContexts builds the service contexts of a Database service named
+// db-testing in the planner namespace, configured as given.
+func buildDatabaseServiceContexts(t *testing.T, svc databaseService) (serviceContextList, error) {
+	ns := "planner"
+	falseBool := false
+	f := mocks.NewFake(t, ns)
+	cr := f.AddMockedDatabaseCR("db-testing", ns).(*unstructured.Unstructured)
+	cr.SetAnnotations(svc.annotations)
+	for k, v := range svc.status {
+		require.NoError(t, unstructured.SetNestedField(cr.Object, v, "status", k))
+	}
+	for name, data := range svc.secrets {
+		f.AddNamespacedMockedSecret(name, ns, data)
+	}
+	services := []v1alpha1.Service{
+		{
+			GroupVersionKind: metav1.GroupVersionKind{
+				Group:   mocks.CRDName,
+				Version: mocks.CRDVersion,
+				Kind:    mocks.CRDKind,
+			},
+			LocalObjectReference: corev1.LocalObjectReference{Name: cr.GetName()},
+			Profile:              svc.profile,
+			Id:                   svc.id,
+		},
+	}
+	return buildServiceContexts(log.NewLog("testBuildServiceContexts"), f.FakeDynClient(), ns, services,
+		&falseBool, svc.detectionMode, svc.providerCtx, testutils.BuildTestRESTMapper())
+}
+
+func TestBuildServiceContextsRequiredValues(t *testing.T) {
+	ns := "planner"
+
+	t.Run("optional and default values", func(t *testing.T) {
+		serviceCtxs, err := buildDatabaseServiceContexts(t, databaseService{annotations: map[string]string{
+			"service.binding/port":   "path={.status.port},optional=true",
+			"service.binding/scheme": "path={.status.scheme},default=postgresql",
+		}})
+		require.NoError(t, err)
+		require.Len(t, serviceCtxs, 1)
+		require.Equal(t, map[string]interface{}{"scheme": "postgresql"}, serviceCtxs[0].envVars)
+	})
+
+	t.Run("missing required values", func(t *testing.T) {
+		_, err := buildDatabaseServiceContexts(t, databaseService{annotations: map[string]string{
+			"service.binding/host":     "path={.status.host}",
+			"service.binding/password": "path={.status.password}",
+			"service.binding/port":     "path={.status.port},optional=true",
+		}})
+		require.Error(t, err)
+		require.Equal(t, errRequiredValuesNotFound{
+			gvk: schema.GroupVersionKind{
+				Group:   mocks.CRDName,
+				Version: mocks.CRDVersion,
+				Kind:    mocks.CRDKind,
+			},
+			namespace: ns,
+			name:      "db-testing",
+			paths:     []string{"status.host", "status.password"},
+		}, err)
+		require.Contains(t, err.Error(), "planner/db-testing")
+	})
+}
+
+func TestBuildServiceContextsFailingDefinitions(t *testing.T) {
+	t.Run("unrelated annotations", func(t *testing.T) {
+		serviceCtxs, err := buildDatabaseServiceContexts(t, databaseService{
+			annotations: map[string]string{
+				"example.com/owner":    "team-a",
+				"service.binding/port": "path={.status.port}",
+			},
+			status: map[string]interface{}{"port": "5432"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"port": "5432"}, serviceCtxs[0].envVars)
+	})
+
+	t.Run("invalid and failing annotations", func(t *testing.T) {
+		_, err := buildDatabaseServiceContexts(t, databaseService{
+			annotations: map[string]string{
+				"service.binding/host":          "path={.status.host}",
+				"service.binding/port":          "path={.status.port},elementType=bogus",
+				"service.binding.script/secret": "fail(\"no secret\")",
+			},
+			status: map[string]interface{}{"host": "db.example.com"},
+		})
+		require.Error(t, err)
+		failedErr := errBindingDefinitionsFailed{}
+		require.True(t, errors.As(err, &failedErr))
+		require.Len(t, failedErr.failures, 2)
+		require.Contains(t, failedErr.failures, "service.binding/port")
+		require.Contains(t, failedErr.failures, "service.binding.script/secret")
+		require.Contains(t, err.Error(), "planner/db-testing")
+		require.Contains(t, err.Error(), "no secret")
+	})
+}
+
+func TestBuildServiceContextsFromProfile(t *testing.T) {
+	binding.DefaultProfileRegistry.SetSource(t.Name(), []binding.Profile{{
+		Group: mocks.CRDName,
+		Kind:  mocks.CRDKind,
+		Annotations: map[string]string{
+			"service.binding/port": "path={.status.port},default=5432",
+		},
+	}})
+	defer binding.DefaultProfileRegistry.SetSource(t.Name(), nil)
+
+	t.Run("profile applies to services without binding annotations", func(t *testing.T) {
+		serviceCtxs, err := buildDatabaseServiceContexts(t, databaseService{annotations: map[string]string{"example.com/owner": "team"}})
+		require.NoError(t, err)
+		require.Len(t, serviceCtxs, 1)
+		require.Equal(t, map[string]interface{}{"port": "5432"}, serviceCtxs[0].envVars)
+	})
+
+	t.Run("profile is ignored for services with binding annotations", func(t *testing.T) {
+		serviceCtxs, err := buildDatabaseServiceContexts(t, databaseService{annotations: map[string]string{
+			"service.binding/scheme": "path={.status.scheme},default=postgresql",
+		}})
+		require.NoError(t, err)
+		require.Len(t, serviceCtxs, 1)
+		require.Equal(t, map[string]interface{}{"scheme": "postgresql"}, serviceCtxs[0].envVars)
+	})
+}
+
+func TestBuildServiceContextsWithCredentialProfile(t *testing.T) {
+	ns := "planner"
+
+	readonly := databaseService{
+		annotations: map[string]string{
+			"service.binding/credentials":                  "path={.status.dbCredentials},objectType=Secret",
+			"service.binding.profile/readonly/credentials": "path={.status.readonlyCredentials},objectType=Secret",
+		},
+		status: map[string]interface{}{"readonlyCredentials": "db-readonly-credentials"},
+		secrets: map[string]map[string][]byte{
+			"db-credentials": nil,
+			"db-readonly-credentials": {
+				"username": []byte("reporter"),
+				"password": []byte("secret"),
+			},
+		},
+	}
+
+	t.Run("without profile", func(t *testing.T) {
+		serviceCtxs, err := buildDatabaseServiceContexts(t, readonly)
+		require.NoError(t, err)
+		require.Len(t, serviceCtxs, 1)
+		require.Equal(t, map[string]interface{}{
+			"username": "user",
+			"password": "password",
+		}, serviceCtxs[0].envVars)
+	})
+
+	t.Run("with profile", func(t *testing.T) {
+		profile := "readonly"
+		svc := readonly
+		svc.profile = &profile
+		serviceCtxs, err := buildDatabaseServiceContexts(t, svc)
+		require.NoError(t, err)
+		require.Len(t, serviceCtxs, 1)
+		require.Equal(t, map[string]interface{}{
+			"username": "reporter",
+			"password": "secret",
+		}, serviceCtxs[0].envVars)
+	})
+
+	t.Run("with unknown profile", func(t *testing.T) {
+		profile := "admin"
+		svc := readonly
+		svc.profile = &profile
+		_, err := buildDatabaseServiceContexts(t, svc)
+		require.Equal(t, errCredentialProfileNotFound{
+			gvk: schema.GroupVersionKind{
+				Group:   mocks.CRDName,
+				Version: mocks.CRDVersion,
+				Kind:    mocks.CRDKind,
+			},
+			namespace: ns,
+			name:      "db-testing",
+			profile:   "admin",
+		}, err)
+	})
+}
+
+func TestBuildServiceContextsDetectingBindingFields(t *testing.T) {
+	ns := "planner"
+
+	dbCredentials := map[string]map[string][]byte{"db-credentials": nil}
+	inferred := map[string]string{
+		"service.binding/username": "path={.status.dbCredentials},objectType=Secret,sourceKey=username",
+		"service.binding/password": "path={.status.dbCredentials},objectType=Secret,sourceKey=password",
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		serviceCtxs, err := buildDatabaseServiceContexts(t, databaseService{secrets: dbCredentials})
+		require.NoError(t, err)
+		require.Len(t, serviceCtxs, 1)
+		require.Empty(t, serviceCtxs[0].envVars)
+		require.Empty(t, serviceCtxs.getInferredBindings())
+	})
+
+	t.Run("propose", func(t *testing.T) {
+		serviceCtxs, err := buildDatabaseServiceContexts(t, databaseService{secrets: dbCredentials, detectionMode: v1alpha1.DetectionModePropose})
+		require.NoError(t, err)
+		require.Len(t, serviceCtxs, 1)
+		require.Empty(t, serviceCtxs[0].envVars)
+		require.Equal(t, []v1alpha1.InferredBinding{{
+			GroupVersionKind: metav1.GroupVersionKind{
+				Group:   mocks.CRDName,
+				Version: mocks.CRDVersion,
+				Kind:    mocks.CRDKind,
+			},
+			Namespace:   ns,
+			Name:        "db-testing",
+			Annotations: inferred,
+		}}, serviceCtxs.getInferredBindings())
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		serviceCtxs, err := buildDatabaseServiceContexts(t, databaseService{secrets: dbCredentials, detectionMode: v1alpha1.DetectionModeApply})
+		require.NoError(t, err)
+		require.Len(t, serviceCtxs, 1)
+		require.Equal(t, map[string]interface{}{
+			"username": "user",
+			"password": "password",
+		}, serviceCtxs[0].envVars)
+		require.Len(t, serviceCtxs.getInferredBindings(), 1)
+	})
+
+	t.Run("services with binding annotations are not inspected", func(t *testing.T) {
+		serviceCtxs, err := buildDatabaseServiceContexts(t, databaseService{
+			annotations: map[string]string{
+				"service.binding/scheme": "path={.status.scheme},default=postgresql",
+			},
+			detectionMode: v1alpha1.DetectionModeApply,
+		})
+		require.NoError(t, err)
+		require.Len(t, serviceCtxs, 1)
+		require.Equal(t, map[string]interface{}{"scheme": "postgresql"}, serviceCtxs[0].envVars)
+		require.Empty(t, serviceCtxs.getInferredBindings())
+	})
+}
+
+func TestBuildServiceContextsWithBindingProvider(t *testing.T) {
+	ns := "planner"
+
+	var received binding.ProviderRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		if r.URL.Path != "/token" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"token":"s3cr3t","host":"provided"}`))
+	}))
+	defer srv.Close()
+
+	sbr := &v1alpha1.ServiceBinding{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "binding"},
+		Spec: v1alpha1.ServiceBindingSpec{
+			Application: &v1alpha1.Application{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "app"},
+				GroupVersionResource: metav1.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+			},
+		},
+	}
+	provider := binding.NewHTTPBindingProvider(binding.HTTPProviderOptions{AllowedURLs: []string{srv.URL}})
+	id := "db"
+	withEndpoint := func(endpoint string, providerCtx *bindingProviderContext) databaseService {
+		return databaseService{
+			annotations: map[string]string{
+				"service.binding/host":     "path={.metadata.name}",
+				"service.binding/name":     "path={.metadata.name}",
+				binding.ProviderAnnotation: endpoint,
+			},
+			id:          &id,
+			providerCtx: providerCtx,
+		}
+	}
+
+	t.Run("provided values are merged", func(t *testing.T) {
+		serviceCtxs, err := buildDatabaseServiceContexts(t, withEndpoint(srv.URL+"/token", newBindingProviderContext(provider, sbr)))
+		require.NoError(t, err)
+		require.Len(t, serviceCtxs, 1)
+		require.Equal(t, map[string]interface{}{
+			"host":  "provided",
+			"name":  "db-testing",
+			"token": "s3cr3t",
+		}, serviceCtxs[0].envVars)
+
+		require.Equal(t, binding.ProviderBinding{
+			Namespace: ns,
+			Name:      "binding",
+			ServiceID: id,
+			Application: &binding.ProviderApplication{
+				Group:    "apps",
+				Version:  "v1",
+				Resource: "deployments",
+				Name:     "app",
+			},
+		}, received.Binding)
+		require.Equal(t, "db-testing", received.Service["metadata"].(map[string]interface{})["name"])
+	})
+
+	t.Run("provider failure", func(t *testing.T) {
+		_, err := buildDatabaseServiceContexts(t, withEndpoint(srv.URL+"/unavailable", newBindingProviderContext(provider, sbr)))
+		require.Error(t, err)
+		require.IsType(t, errBindingProviderFailed{}, err)
+	})
+
+	t.Run("endpoint not allowed", func(t *testing.T) {
+		_, err := buildDatabaseServiceContexts(t, withEndpoint("https://provider.example.com/token", newBindingProviderContext(provider, sbr)))
+		require.Error(t, err)
+		require.True(t, binding.IsErrBindingProviderNotAllowed(err.(errBindingProviderFailed).err))
+	})
+
+	t.Run("no provider configured", func(t *testing.T) {
+		serviceCtxs, err := buildDatabaseServiceContexts(t, withEndpoint(srv.URL+"/token", newBindingProviderContext(nil, sbr)))
+		require.NoError(t, err)
+		require.Len(t, serviceCtxs, 1)
+		require.Equal(t, map[string]interface{}{
+			"host": "db-testing",
+			"name": "db-testing",
+		}, serviceCtxs[0].envVars)
+	})
+}
+
+func TestBuildServiceContextsFromTypedCRD(t *testing.T) {
+	logger := log.NewLog("testBuildServiceContextsFromTypedCRD")
+	restMapper := testutils.BuildTestRESTMapper()
+	falseBool := false
+	ns := "planner"
+
+	// the CRD goes through its API types, as it does when stored by the API server: binding
+	// metadata has to be declared in annotations, the schema having no room for it
+	typed := mocks.DatabaseCRDMock(ns)
+	typed.SetAnnotations(map[string]string{
+		"service.binding.descriptors/status.dbCredentials": "urn:alm:descriptor:io.kubernetes:Secret," +
+			"service.binding:username:sourceKey=username,service.binding:password:sourceKey=password",
+		"service.binding/port":          "path={.status.port},default=5432",
+		"v1alpha1.service.binding/port": "path={.status.port},default=5433",
+		"v1beta1.service.binding/host":  "path={.status.host}",
+	})
+	typed.Spec.Versions[0].Schema = &apiextensionv1.CustomResourceValidation{
+		OpenAPIV3Schema: &apiextensionv1.JSONSchemaProps{
+			Type: "object",
+			Properties: map[string]apiextensionv1.JSONSchemaProps{
+				"status": {
+					Type: "object",
+					Properties: map[string]apiextensionv1.JSONSchemaProps{
+						"dbCredentials": {Type: "string"},
+						"port":          {Type: "string"},
+					},
+				},
+			},
+		},
+	}
+	u, err := converter.ToUnstructured(&typed)
+	require.NoError(t, err)
+
+	f := mocks.NewFake(t, ns)
+	crd := f.AddMockedUnstructuredDatabaseCRD()
+	crd.Object = u.Object
+
+	cr := f.AddMockedDatabaseCR("db-testing", ns).(*unstructured.Unstructured)
+	f.AddNamespacedMockedSecret("db-credentials", ns, nil)
+	services := []v1alpha1.Service{
+		{
+			GroupVersionKind: metav1.GroupVersionKind{
+				Group:   mocks.CRDName,
+				Version: mocks.CRDVersion,
+				Kind:    mocks.CRDKind,
+			},
+			LocalObjectReference: corev1.LocalObjectReference{Name: cr.GetName()},
+		},
+	}
+
+	serviceCtxs, err := buildServiceContexts(logger, f.FakeDynClient(), ns, services, &falseBool, "", nil, restMapper)
+	require.NoError(t, err)
+	require.Len(t, serviceCtxs, 1)
+	require.Equal(t, map[string]interface{}{
+		"username": "user",
+		"password": "password",
+		"port":     "5433",
+	}, serviceCtxs[0].envVars)
+}
+
 var trueBool = true
 
 func TestFindOwnedResourcesCtxs_ConfigMap(t *testing.T) {
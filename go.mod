@@ -13,10 +13,12 @@ require (
 	github.com/operator-framework/api v0.3.8
 	github.com/pkg/errors v0.9.1
 	github.com/stretchr/testify v1.6.1
+	go.starlark.net v0.0.0-20200901195727-6e684ef5eeee
 	gotest.tools v2.2.0+incompatible
 	k8s.io/api v0.19.2
 	k8s.io/apiextensions-apiserver v0.19.2
 	k8s.io/apimachinery v0.19.2
 	k8s.io/client-go v0.19.2
 	sigs.k8s.io/controller-runtime v0.6.4
+	sigs.k8s.io/yaml v1.2.0
 )
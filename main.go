@@ -63,6 +63,15 @@ func getWatchNamespace() (string, error) {
 	return ns, nil
 }
 
+// getOperatorNamespace returns the Namespace the operator is running in, falling back to the
+// Namespace it is watching.
+func getOperatorNamespace(watchNamespace string) string {
+	if ns, found := os.LookupEnv("OPERATOR_NAMESPACE"); found && len(ns) > 0 {
+		return ns
+	}
+	return watchNamespace
+}
+
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
@@ -110,6 +119,18 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "ServiceBinding")
 		os.Exit(1)
 	}
+	if err = (&controllers.BindableKindsUpdater{}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create updater", "updater", "BindableKinds")
+		os.Exit(1)
+	}
+	if operatorNamespace := getOperatorNamespace(watchNamespace); len(operatorNamespace) > 0 {
+		if err = (&controllers.ProfileLoader{Namespace: operatorNamespace}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create loader", "loader", "Profiles")
+			os.Exit(1)
+		}
+	} else {
+		setupLog.Info("OPERATOR_NAMESPACE is not set, binding profiles will not be loaded from ConfigMaps")
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("health", healthz.Ping); err != nil {
@@ -12,6 +12,7 @@ type annotationBackedDefinitionBuilder struct {
 	kubeClient dynamic.Interface
 	name       string
 	value      string
+	registry   *DefinitionRegistry
 }
 
 var _ DefinitionBuilder = (*annotationBackedDefinitionBuilder)(nil)
@@ -19,17 +20,47 @@ var _ DefinitionBuilder = (*annotationBackedDefinitionBuilder)(nil)
 type modelKey string
 
 const (
-	pathModelKey        modelKey = "path"
-	objectTypeModelKey  modelKey = "objectType"
-	sourceKeyModelKey   modelKey = "sourceKey"
-	sourceValueModelKey modelKey = "sourceValue"
-	elementTypeModelKey modelKey = "elementType"
-	AnnotationPrefix             = "service.binding"
+	pathModelKey           modelKey = "path"
+	objectTypeModelKey     modelKey = "objectType"
+	sourceKeyModelKey      modelKey = "sourceKey"
+	sourceValueModelKey    modelKey = "sourceValue"
+	elementTypeModelKey    modelKey = "elementType"
+	bindAsModelKey         modelKey = "bindAs"
+	optionalModelKey       modelKey = "optional"
+	defaultModelKey        modelKey = "default"
+	arrayFormatModelKey    modelKey = "arrayFormat"
+	arraySeparatorModelKey modelKey = "arraySeparator"
+	AnnotationPrefix                = "service.binding"
+	// JSONAnnotationPrefix is the prefix of annotations declaring the binding model as a JSON
+	// object, e.g. `service.binding.json/port: {"path": "{.status.port}"}`.
+	JSONAnnotationPrefix = "service.binding.json"
 )
 
+// IsBindingAnnotation evaluates whether the given annotation name declares a binding, either in the
+// legacy or in the JSON syntax, through a script, or as part of a credential profile.
+func IsBindingAnnotation(name string) bool {
+	prefix := strings.SplitN(name, "/", 2)[0]
+	return isDefinitionAnnotationPrefix(prefix) || prefix == ProfileAnnotationPrefix
+}
+
+// isDefinitionAnnotationPrefix evaluates whether annotations with the given prefix can be built
+// into a Definition.
+func isDefinitionAnnotationPrefix(prefix string) bool {
+	return prefix == AnnotationPrefix || prefix == JSONAnnotationPrefix || prefix == ScriptAnnotationPrefix
+}
+
+func (m *annotationBackedDefinitionBuilder) isJSON() bool {
+	return strings.SplitN(m.name, "/", 2)[0] == JSONAnnotationPrefix
+}
+
+func (m *annotationBackedDefinitionBuilder) isScript() bool {
+	return strings.SplitN(m.name, "/", 2)[0] == ScriptAnnotationPrefix
+}
+
 func (m *annotationBackedDefinitionBuilder) outputName() (string, error) {
-	// bail out in the case the annotation name doesn't start with "service.binding"
-	if m.name != AnnotationPrefix && !strings.HasPrefix(m.name, AnnotationPrefix+"/") {
+	// bail out in the case the annotation name doesn't start with "service.binding",
+	// "service.binding.json" or "service.binding.script"
+	if !isDefinitionAnnotationPrefix(strings.SplitN(m.name, "/", 2)[0]) {
 		return "", fmt.Errorf("can't process annotation with name %q", m.name)
 	}
 
@@ -41,67 +72,56 @@ func (m *annotationBackedDefinitionBuilder) outputName() (string, error) {
 }
 
 func (m *annotationBackedDefinitionBuilder) Build() (Definition, error) {
+	outputName, mod, err := m.parse()
+	if err != nil {
+		return nil, err
+	}
+	return m.build(outputName, mod)
+}
 
+// parse extracts the output name and the binding model from the annotation.
+func (m *annotationBackedDefinitionBuilder) parse() (string, *model, error) {
 	outputName, err := m.outputName()
 	if err != nil {
-		return nil, err
+		return "", nil, err
 	}
 
-	mod, err := newModel(m.value)
+	var mod *model
+	if m.isScript() {
+		mod, err = newScriptModel(outputName, m.value)
+	} else if m.isJSON() {
+		mod, err = newModelFromJSON(m.value)
+	} else {
+		mod, err = newModel(m.value)
+	}
 	if err != nil {
-		return nil, errors.Wrapf(err, "could not create binding model for annotation key %s and value %s", m.name, m.value)
+		return "", nil, errors.Wrapf(err, "could not create binding model for annotation key %s and value %s", m.name, m.value)
 	}
 
-	if len(outputName) == 0 {
+	if len(outputName) == 0 && len(mod.path) > 0 {
 		outputName = mod.path[len(mod.path)-1]
 	}
 
-	switch {
-	case mod.isStringElementType() && mod.isStringObjectType():
-		return &stringDefinition{
-			outputName: outputName,
-			path:       mod.path,
-		}, nil
-
-	case mod.isStringElementType() && mod.hasDataField():
-		return &stringFromDataFieldDefinition{
-			kubeClient: m.kubeClient,
-			objectType: mod.objectType,
-			outputName: outputName,
-			path:       mod.path,
-			sourceKey:  mod.sourceKey,
-		}, nil
-
-	case mod.isMapElementType() && mod.hasDataField():
-		return &mapFromDataFieldDefinition{
-			kubeClient:  m.kubeClient,
-			objectType:  mod.objectType,
-			outputName:  outputName,
-			path:        mod.path,
-			sourceValue: mod.sourceValue,
-		}, nil
-
-	case mod.isMapElementType() && mod.isStringObjectType():
-		return &stringOfMapDefinition{
-			outputName: outputName,
-			path:       mod.path,
-		}, nil
-
-	case mod.isSliceOfMapsElementType():
-		return &sliceOfMapsFromPathDefinition{
-			outputName:  outputName,
-			path:        mod.path,
-			sourceKey:   mod.sourceKey,
-			sourceValue: mod.sourceValue,
-		}, nil
-
-	case mod.isSliceOfStringsElementType():
-		return &sliceOfStringsFromPathDefinition{
-			outputName:  outputName,
-			path:        mod.path,
-			sourceValue: mod.sourceValue,
-		}, nil
+	return outputName, mod, nil
+}
+
+func (m *annotationBackedDefinitionBuilder) build(outputName string, mod *model) (Definition, error) {
+	registry := m.registry
+	if registry == nil {
+		registry = DefaultDefinitionRegistry
 	}
 
-	panic(fmt.Sprintf("Annotation %s=%s not implemented!", m.name, m.value))
+	return registry.Build(DefinitionOptions{
+		KubeClient:  m.kubeClient,
+		OutputName:  outputName,
+		Path:        mod.path,
+		Query:       mod.query,
+		ElementType: string(mod.elementType),
+		ObjectType:  string(mod.objectType),
+		SourceKey:   mod.sourceKey,
+		SourceValue: mod.sourceValue,
+		Script:      mod.script,
+		program:     mod.program,
+		BindAs:      mod.bindAs,
+	})
 }
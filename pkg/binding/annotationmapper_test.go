@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 func TestAnnotationBackedBuilderInvalidAnnotation(t *testing.T) {
@@ -46,6 +47,76 @@ func TestAnnotationBackedBuilderInvalidAnnotation(t *testing.T) {
 				name: "other.prefix",
 			},
 		},
+		{
+			description: "invalid optional",
+			builder: &annotationBackedDefinitionBuilder{
+				name:  "service.binding",
+				value: "path={.status.secret},optional=maybe",
+			},
+		},
+		{
+			description: "invalid bindAs",
+			builder: &annotationBackedDefinitionBuilder{
+				name:  "service.binding",
+				value: "path={.status.secret},bindAs=carrierPigeon",
+			},
+		},
+		{
+			description: "invalid arrayFormat",
+			builder: &annotationBackedDefinitionBuilder{
+				name:  "service.binding",
+				value: "path={.status.hosts},elementType=sliceOfStrings,arrayFormat=csv",
+			},
+		},
+		{
+			description: "arraySeparator without join arrayFormat",
+			builder: &annotationBackedDefinitionBuilder{
+				name:  "service.binding",
+				value: "path={.status.hosts},elementType=sliceOfStrings,arraySeparator=;",
+			},
+		},
+		{
+			description: "malformed JSON",
+			builder: &annotationBackedDefinitionBuilder{
+				name:  "service.binding.json/secret",
+				value: `{"path": "{.status.secret}"`,
+			},
+		},
+		{
+			description: "JSON without path",
+			builder: &annotationBackedDefinitionBuilder{
+				name:  "service.binding.json/secret",
+				value: `{"objectType": "Secret"}`,
+			},
+		},
+		{
+			description: "JSON with unknown field",
+			builder: &annotationBackedDefinitionBuilder{
+				name:  "service.binding.json/secret",
+				value: `{"path": "{.status.secret}", "objectTyp": "Secret"}`,
+			},
+		},
+		{
+			description: "JSON with field of unexpected type",
+			builder: &annotationBackedDefinitionBuilder{
+				name:  "service.binding.json/secret",
+				value: `{"path": "{.status.secret}", "optional": "yes"}`,
+			},
+		},
+		{
+			description: "JSON with trailing data",
+			builder: &annotationBackedDefinitionBuilder{
+				name:  "service.binding.json/secret",
+				value: `{"path": "{.status.secret}"} {}`,
+			},
+		},
+		{
+			description: "JSON with invalid delivery",
+			builder: &annotationBackedDefinitionBuilder{
+				name:  "service.binding.json/secret",
+				value: `{"path": "{.status.secret}", "delivery": "carrierPigeon"}`,
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -56,6 +127,135 @@ func TestAnnotationBackedBuilderInvalidAnnotation(t *testing.T) {
 	}
 }
 
+func TestAnnotationBackedBuilderUnsupportedDefinition(t *testing.T) {
+	builder := &annotationBackedDefinitionBuilder{
+		name:  "service.binding",
+		value: "path={.status.secret},elementType=sliceOfSecrets",
+	}
+	_, err := builder.Build()
+	require.Error(t, err)
+	require.True(t, IsErrUnsupportedDefinition(err))
+}
+
+func TestAnnotationBackedBuilderInvalidJSON(t *testing.T) {
+	for description, tc := range map[string]struct {
+		value       string
+		expectedErr string
+	}{
+		"missing path": {
+			value:       `{"objectType": "Secret"}`,
+			expectedErr: "path is required",
+		},
+		"invalid delivery": {
+			value:       `{"path": "{.status.secret}", "delivery": "carrierPigeon"}`,
+			expectedErr: `delivery has invalid value: "carrierPigeon"`,
+		},
+		"invalid arrayFormat": {
+			value:       `{"path": "{.status.hosts}", "elementType": "sliceOfStrings", "arrayFormat": "csv"}`,
+			expectedErr: `arrayFormat has invalid value: "csv"`,
+		},
+		"sliceOfMaps without sourceKey": {
+			value:       `{"path": "{.status.bootstrap}", "elementType": "sliceOfMaps", "sourceValue": "url"}`,
+			expectedErr: `sourceKey is required for elementType "sliceOfMaps"`,
+		},
+		"sliceOfMaps without sourceValue": {
+			value:       `{"path": "{.status.bootstrap}", "elementType": "sliceOfMaps", "sourceKey": "type"}`,
+			expectedErr: `sourceValue is required for elementType "sliceOfMaps"`,
+		},
+		"invalid elementType": {
+			value:       `{"path": "{.status.secret}", "elementType": "sliceOfSecrets"}`,
+			expectedErr: `elementType "sliceOfSecrets"`,
+		},
+		"invalid objectType": {
+			value:       `{"path": "{.status.secret}", "objectType": "Route"}`,
+			expectedErr: `objectType "Route"`,
+		},
+		"malformed path": {
+			value:       `{"path": "{.status.hosts[?(@.primary}"}`,
+			expectedErr: "path has invalid syntax",
+		},
+		"path with text outside of the expression": {
+			value:       `{"path": "host: {.status.host}"}`,
+			expectedErr: "path has invalid syntax",
+		},
+	} {
+		t.Run(description, func(t *testing.T) {
+			builder := &annotationBackedDefinitionBuilder{
+				name:  "service.binding.json/secret",
+				value: tc.value,
+			}
+			_, err := builder.Build()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expectedErr)
+		})
+	}
+}
+
+func TestAnnotationBackedBuilderJSONPath(t *testing.T) {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"tls.crt": "certificate",
+			"listeners": []interface{}{
+				map[string]interface{}{"name": "plain", "host": "db", "port": int64(5432)},
+				map[string]interface{}{"name": "tls", "host": "db-tls", "port": int64(5433)},
+				map[string]interface{}{"name": "tls", "host": "db-tls-2", "port": int64(5434)},
+			},
+		},
+	}}
+
+	for description, tc := range map[string]struct {
+		name          string
+		value         string
+		expectedValue interface{}
+	}{
+		"key holding dots": {
+			name:          "service.binding.json",
+			value:         `{"path": "{.status.tls\\.crt}"}`,
+			expectedValue: map[string]interface{}{"tls.crt": "certificate"},
+		},
+		"key holding dots in brackets": {
+			name:          "service.binding.json/crt",
+			value:         `{"path": "{.status['tls\\.crt']}"}`,
+			expectedValue: map[string]interface{}{"crt": "certificate"},
+		},
+		"filter": {
+			name:          "service.binding.json/port",
+			value:         `{"path": "{.status.listeners[?(@.name==\"plain\")].port}"}`,
+			expectedValue: map[string]interface{}{"port": "5432"},
+		},
+		"index": {
+			name:          "service.binding.json",
+			value:         `{"path": "{.status.listeners[1].host}"}`,
+			expectedValue: map[string]interface{}{"host": "db-tls"},
+		},
+		"filter matching several elements": {
+			name:          "service.binding.json/hosts",
+			value:         `{"path": "{.status.listeners[?(@.name==\"tls\")].host}", "elementType": "sliceOfStrings"}`,
+			expectedValue: map[string]interface{}{"hosts": []interface{}{"db-tls", "db-tls-2"}},
+		},
+	} {
+		t.Run(description, func(t *testing.T) {
+			builder := &annotationBackedDefinitionBuilder{name: tc.name, value: tc.value}
+			d, err := builder.Build()
+			require.NoError(t, err)
+			val, err := d.Apply(u)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedValue, val.Get())
+		})
+	}
+
+	t.Run("filter matching no element", func(t *testing.T) {
+		builder := &annotationBackedDefinitionBuilder{
+			name:  "service.binding.json/port",
+			value: `{"path": "{.status.listeners[?(@.name==\"admin\")].port}"}`,
+		}
+		d, err := builder.Build()
+		require.NoError(t, err)
+		_, err = d.Apply(u)
+		require.Equal(t, ErrValueNotFound, err)
+	})
+}
+
 func TestAnnotationBackedBuilderValidAnnotations(t *testing.T) {
 	type args struct {
 		description   string
@@ -265,6 +465,89 @@ func TestAnnotationBackedBuilderValidAnnotations(t *testing.T) {
 				sourceValue: "url",
 			},
 		},
+
+		{
+			description: "map from data field definition bound as volume mount",
+			builder: &annotationBackedDefinitionBuilder{
+				name:  "service.binding/ca.crt",
+				value: "path={.status.tls},objectType=Secret,sourceValue=ca.crt,bindAs=volumemount",
+			},
+			expectedValue: &mapFromDataFieldDefinition{
+				kubeClient:  nil,
+				objectType:  secretObjectType,
+				outputName:  "ca.crt",
+				path:        []string{"status", "tls"},
+				sourceValue: "ca.crt",
+				bindAs:      TypeVolumeMount,
+			},
+		},
+
+		{
+			description: "string definition bound as env var using alias",
+			builder: &annotationBackedDefinitionBuilder{
+				name:  "service.binding/host",
+				value: "path={.status.host},bindAs=envVar",
+			},
+			expectedValue: &stringDefinition{
+				outputName: "host",
+				path:       []string{"status", "host"},
+				bindAs:     TypeEnvVar,
+			},
+		},
+
+		{
+			description: "string definition declared as JSON",
+			builder: &annotationBackedDefinitionBuilder{
+				name:  "service.binding.json/host",
+				value: `{"path": "{.status.host}", "delivery": "env"}`,
+			},
+			expectedValue: &stringDefinition{
+				outputName: "host",
+				path:       []string{"status", "host"},
+				bindAs:     TypeEnvVar,
+			},
+		},
+
+		{
+			description: "string definition declared as JSON with default output name",
+			builder: &annotationBackedDefinitionBuilder{
+				name:  "service.binding.json",
+				value: `{"path": "{.status.host}"}`,
+			},
+			expectedValue: &stringDefinition{
+				outputName: "host",
+				path:       []string{"status", "host"},
+			},
+		},
+
+		{
+			description: "map from data field definition declared as JSON",
+			builder: &annotationBackedDefinitionBuilder{
+				name:  "service.binding.json/ca.crt",
+				value: `{"path": "{.status.tls}", "objectType": "Secret", "sourceValue": "ca.crt", "delivery": "volumemount"}`,
+			},
+			expectedValue: &mapFromDataFieldDefinition{
+				objectType:  secretObjectType,
+				outputName:  "ca.crt",
+				path:        []string{"status", "tls"},
+				sourceValue: "ca.crt",
+				bindAs:      TypeVolumeMount,
+			},
+		},
+
+		{
+			description: "slice of maps from path definition declared as JSON",
+			builder: &annotationBackedDefinitionBuilder{
+				name:  "service.binding.json/bootstrap",
+				value: `{"path": "{.status.bootstrap}", "elementType": "sliceOfMaps", "sourceKey": "type", "sourceValue": "url"}`,
+			},
+			expectedValue: &sliceOfMapsFromPathDefinition{
+				outputName:  "bootstrap",
+				path:        []string{"status", "bootstrap"},
+				sourceKey:   "type",
+				sourceValue: "url",
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,96 @@
+package binding
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// ProfileAnnotationPrefix is the prefix of annotations grouping binding definitions in named
+	// credential profiles, in the form "service.binding.profile/<profile>[/<name>]"; values use
+	// either the legacy or the JSON syntax.
+	ProfileAnnotationPrefix = "service.binding.profile"
+	// DefaultCredentialProfile is the credential profile used when none has been selected.
+	DefaultCredentialProfile = "default"
+)
+
+// ErrCredentialProfileNotFound is returned when the selected credential profile hasn't been
+// declared by the service.
+type ErrCredentialProfileNotFound string
+
+func (e ErrCredentialProfileNotFound) Error() string {
+	return fmt.Sprintf("credential profile %q not declared by the service", string(e))
+}
+
+func IsErrCredentialProfileNotFound(err error) bool {
+	_, ok := err.(ErrCredentialProfileNotFound)
+	return ok
+}
+
+// parseProfileAnnotation extracts the credential profile and the binding annotation name from the
+// given profile annotation name.
+func parseProfileAnnotation(name string) (profile string, annotationName string, ok bool) {
+	p := strings.SplitN(name, "/", 3)
+	if p[0] != ProfileAnnotationPrefix || len(p) < 2 || len(p[1]) == 0 {
+		return "", "", false
+	}
+	if len(p) == 3 && len(p[2]) > 0 {
+		return p[1], p[2], true
+	}
+	return p[1], "", true
+}
+
+// profileDefinitionAnnotation returns the name of the binding annotation equivalent to a profile
+// annotation, picking the JSON syntax for JSON object values.
+func profileDefinitionAnnotation(name string, value string) string {
+	prefix := AnnotationPrefix
+	if strings.HasPrefix(strings.TrimSpace(value), "{") {
+		prefix = JSONAnnotationPrefix
+	}
+	if len(name) == 0 {
+		return prefix
+	}
+	return prefix + "/" + name
+}
+
+// SelectCredentialProfile returns the given annotations with the ones declared for the selected
+// credential profile translated to binding annotations, overriding the binding annotations shared
+// by all profiles; annotations of other profiles are dropped. When profile is empty
+// DefaultCredentialProfile is used, if declared; ErrCredentialProfileNotFound is returned when the
+// explicitly selected profile hasn't been declared.
+func SelectCredentialProfile(anns map[string]string, profile string) (map[string]string, error) {
+	selected := profile
+	if len(selected) == 0 {
+		selected = DefaultCredentialProfile
+	}
+
+	out := make(map[string]string, len(anns))
+	profileAnns := make(map[string]string)
+	found := false
+	for k, v := range anns {
+		p, name, ok := parseProfileAnnotation(k)
+		if !ok {
+			out[k] = v
+			continue
+		}
+		if p == selected {
+			found = true
+			profileAnns[profileDefinitionAnnotation(name, v)] = v
+		}
+	}
+
+	if !found && len(profile) > 0 {
+		return nil, ErrCredentialProfileNotFound(profile)
+	}
+
+	for k, v := range profileAnns {
+		// a definition in the profile replaces the shared definition with the same output name,
+		// regardless of the syntax each of them has been declared with
+		name := strings.TrimPrefix(strings.TrimPrefix(k, JSONAnnotationPrefix), AnnotationPrefix)
+		delete(out, AnnotationPrefix+name)
+		delete(out, JSONAnnotationPrefix+name)
+		out[k] = v
+	}
+
+	return out, nil
+}
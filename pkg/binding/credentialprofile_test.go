@@ -0,0 +1,85 @@
+package binding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectCredentialProfile(t *testing.T) {
+	anns := map[string]string{
+		"service.binding/host":                       "path={.status.host}",
+		"service.binding/username":                   "path={.status.admin.username}",
+		"service.binding.profile/default/password":   "path={.status.admin.password}",
+		"service.binding.profile/readonly/username":  "path={.status.readonly.username}",
+		"service.binding.profile/readonly/password":  "path={.status.readonly.password}",
+		"service.binding.profile/readonly/options":   `{"path": "{.status.options}", "default": "readOnly=true,ssl=true"}`,
+		"service.binding.profile/readonly":           "path={.status.readonly.secret},objectType=Secret",
+		"service.binding.profile/reporting/database": "path={.status.reportingDatabase}",
+		"example.com/owner":                          "team",
+	}
+
+	t.Run("default profile", func(t *testing.T) {
+		got, err := SelectCredentialProfile(anns, "")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"service.binding/host":     "path={.status.host}",
+			"service.binding/username": "path={.status.admin.username}",
+			"service.binding/password": "path={.status.admin.password}",
+			"example.com/owner":        "team",
+		}, got)
+	})
+
+	t.Run("selected profile overrides shared annotations", func(t *testing.T) {
+		got, err := SelectCredentialProfile(anns, "readonly")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"service.binding/host":         "path={.status.host}",
+			"service.binding/username":     "path={.status.readonly.username}",
+			"service.binding/password":     "path={.status.readonly.password}",
+			"service.binding.json/options": `{"path": "{.status.options}", "default": "readOnly=true,ssl=true"}`,
+			"service.binding":              "path={.status.readonly.secret},objectType=Secret",
+			"example.com/owner":            "team",
+		}, got)
+	})
+
+	t.Run("shared JSON annotation overridden by profile", func(t *testing.T) {
+		got, err := SelectCredentialProfile(map[string]string{
+			"service.binding.json/username":             `{"path": "{.status.admin.username}"}`,
+			"service.binding.profile/readonly/username": "path={.status.readonly.username}",
+		}, "readonly")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"service.binding/username": "path={.status.readonly.username}",
+		}, got)
+	})
+
+	t.Run("no profiles declared", func(t *testing.T) {
+		got, err := SelectCredentialProfile(map[string]string{"service.binding/host": "path={.status.host}"}, "")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"service.binding/host": "path={.status.host}"}, got)
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		_, err := SelectCredentialProfile(anns, "admin")
+		require.Error(t, err)
+		require.True(t, IsErrCredentialProfileNotFound(err))
+		require.Equal(t, ErrCredentialProfileNotFound("admin"), err)
+	})
+}
+
+func TestDescribeProfileAnnotation(t *testing.T) {
+	d, err := Describe("service.binding.profile/readonly/password", "path={.status.readonly.password},bindAs=env")
+	require.NoError(t, err)
+	require.Equal(t, &Description{
+		Name:        "password",
+		Path:        "status.readonly.password",
+		ElementType: "string",
+		ObjectType:  "string",
+		BindAs:      TypeEnvVar,
+		Profile:     "readonly",
+	}, d)
+
+	_, err = Describe("service.binding.profile//password", "path={.status.readonly.password}")
+	require.Error(t, err)
+}
@@ -5,11 +5,13 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"strings"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/jsonpath"
 )
 
 type objectType string
@@ -39,8 +41,67 @@ const (
 	stringElementType elementType = "string"
 )
 
+// ErrValueNotFound is returned by a Definition when the value it is meant to collect can't be
+// found; whether that is an error is decided by the binding annotation's optional and default keys.
+var ErrValueNotFound = errors.New("not found")
+
+// nestedField returns the value found at path in obj; query is evaluated instead when informed,
+// and its matches are returned as a slice when there's more than one.
+func nestedField(obj map[string]interface{}, path []string, query *jsonpath.JSONPath) (interface{}, bool, error) {
+	if query == nil {
+		return unstructured.NestedFieldNoCopy(obj, path...)
+	}
+	results, err := query.FindResults(obj)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(results) == 0 || len(results[0]) == 0 {
+		return nil, false, nil
+	}
+	if len(results[0]) == 1 {
+		return results[0][0].Interface(), true, nil
+	}
+	vals := make([]interface{}, 0, len(results[0]))
+	for _, r := range results[0] {
+		vals = append(vals, r.Interface())
+	}
+	return vals, true, nil
+}
+
+// nestedString is nestedField for values expected to be strings.
+func nestedString(obj map[string]interface{}, path []string, query *jsonpath.JSONPath) (string, bool, error) {
+	val, ok, err := nestedField(obj, path, query)
+	if !ok || err != nil {
+		return "", ok, err
+	}
+	s, ok := val.(string)
+	if !ok {
+		return "", false, fmt.Errorf("%v accessor error: %v is of the type %T, expected string", strings.Join(path, "."), val, val)
+	}
+	return s, true, nil
+}
+
+// nestedSlice is nestedField for values expected to be slices; a single value matched by query is
+// returned as a slice holding it.
+func nestedSlice(obj map[string]interface{}, path []string, query *jsonpath.JSONPath) ([]interface{}, bool, error) {
+	val, ok, err := nestedField(obj, path, query)
+	if !ok || err != nil {
+		return nil, ok, err
+	}
+	if s, ok := val.([]interface{}); ok {
+		return s, true, nil
+	}
+	if query != nil {
+		return []interface{}{val}, true, nil
+	}
+	return nil, false, fmt.Errorf("%v accessor error: %v is of the type %T, expected []interface{}", strings.Join(path, "."), val, val)
+}
+
 type Definition interface {
 	GetPath() []string
+	// GetBindAs returns the medium the collected values should be delivered through; an empty
+	// value indicates the default configured in the Service Binding should be used.
+	GetBindAs() BindingType
 	Apply(u *unstructured.Unstructured) (Value, error)
 }
 
@@ -51,6 +112,8 @@ type DefinitionBuilder interface {
 type stringDefinition struct {
 	outputName string
 	path       []string
+	query      *jsonpath.JSONPath
+	bindAs     BindingType
 }
 
 var _ Definition = (*stringDefinition)(nil)
@@ -65,13 +128,15 @@ func (d *stringDefinition) getOutputName() string {
 
 func (d *stringDefinition) GetPath() []string { return d.path[0 : len(d.path)-1] }
 
+func (d *stringDefinition) GetBindAs() BindingType { return d.bindAs }
+
 func (d *stringDefinition) Apply(u *unstructured.Unstructured) (Value, error) {
-	val, ok, err := unstructured.NestedFieldCopy(u.Object, d.path...)
+	val, ok, err := nestedField(u.Object, d.path, d.query)
 	if err != nil {
 		return nil, err
 	}
 	if !ok {
-		return nil, errors.New("not found")
+		return nil, ErrValueNotFound
 	}
 
 	m := map[string]interface{}{
@@ -86,13 +151,17 @@ type stringFromDataFieldDefinition struct {
 	objectType objectType
 	outputName string
 	path       []string
+	query      *jsonpath.JSONPath
 	sourceKey  string
+	bindAs     BindingType
 }
 
 var _ Definition = (*stringFromDataFieldDefinition)(nil)
 
 func (d *stringFromDataFieldDefinition) GetPath() []string { return d.path }
 
+func (d *stringFromDataFieldDefinition) GetBindAs() BindingType { return d.bindAs }
+
 func (d *stringFromDataFieldDefinition) Apply(u *unstructured.Unstructured) (Value, error) {
 	if d.kubeClient == nil {
 		return nil, errors.New("kubeClient required for this functionality")
@@ -105,12 +174,12 @@ func (d *stringFromDataFieldDefinition) Apply(u *unstructured.Unstructured) (Val
 		resource = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "configmaps"}
 	}
 
-	resourceName, ok, err := unstructured.NestedString(u.Object, d.path...)
+	resourceName, ok, err := nestedString(u.Object, d.path, d.query)
 	if err != nil {
 		return nil, err
 	}
 	if !ok {
-		return nil, errors.New("not found")
+		return nil, ErrValueNotFound
 	}
 
 	otherObj, err := d.kubeClient.Resource(resource).Namespace(u.GetNamespace()).Get(context.TODO(), resourceName, v1.GetOptions{})
@@ -123,7 +192,7 @@ func (d *stringFromDataFieldDefinition) Apply(u *unstructured.Unstructured) (Val
 		return nil, err
 	}
 	if !ok {
-		return nil, errors.New("not found")
+		return nil, ErrValueNotFound
 	}
 	if d.objectType == secretObjectType {
 		n, err := base64.StdEncoding.DecodeString(val)
@@ -132,8 +201,12 @@ func (d *stringFromDataFieldDefinition) Apply(u *unstructured.Unstructured) (Val
 		}
 		val = string(n)
 	}
+	outputName := d.outputName
+	if len(outputName) == 0 {
+		outputName = d.sourceKey
+	}
 	v := map[string]interface{}{
-		"": val,
+		outputName: val,
 	}
 	return &value{v: v}, nil
 }
@@ -144,12 +217,16 @@ type mapFromDataFieldDefinition struct {
 	outputName  string
 	sourceValue string
 	path        []string
+	query       *jsonpath.JSONPath
+	bindAs      BindingType
 }
 
 var _ Definition = (*mapFromDataFieldDefinition)(nil)
 
 func (d *mapFromDataFieldDefinition) GetPath() []string { return d.path }
 
+func (d *mapFromDataFieldDefinition) GetBindAs() BindingType { return d.bindAs }
+
 func (d *mapFromDataFieldDefinition) Apply(u *unstructured.Unstructured) (Value, error) {
 	if d.kubeClient == nil {
 		return nil, errors.New("kubeClient required for this functionality")
@@ -162,12 +239,12 @@ func (d *mapFromDataFieldDefinition) Apply(u *unstructured.Unstructured) (Value,
 		resource = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "configmaps"}
 	}
 
-	resourceName, ok, err := unstructured.NestedString(u.Object, d.path...)
+	resourceName, ok, err := nestedString(u.Object, d.path, d.query)
 	if err != nil {
 		return nil, err
 	}
 	if !ok {
-		return nil, errors.New("not found")
+		return nil, ErrValueNotFound
 	}
 
 	otherObj, err := d.kubeClient.Resource(resource).Namespace(u.GetNamespace()).
@@ -181,7 +258,7 @@ func (d *mapFromDataFieldDefinition) Apply(u *unstructured.Unstructured) (Value,
 		return nil, err
 	}
 	if !ok {
-		return nil, errors.New("not found")
+		return nil, ErrValueNotFound
 	}
 
 	outputVal := make(map[string]string)
@@ -213,19 +290,23 @@ func (d *mapFromDataFieldDefinition) Apply(u *unstructured.Unstructured) (Value,
 type stringOfMapDefinition struct {
 	outputName string
 	path       []string
+	query      *jsonpath.JSONPath
+	bindAs     BindingType
 }
 
 var _ Definition = (*stringOfMapDefinition)(nil)
 
 func (d *stringOfMapDefinition) GetPath() []string { return d.path }
 
+func (d *stringOfMapDefinition) GetBindAs() BindingType { return d.bindAs }
+
 func (d *stringOfMapDefinition) Apply(u *unstructured.Unstructured) (Value, error) {
-	val, ok, err := unstructured.NestedFieldNoCopy(u.Object, d.path...)
+	val, ok, err := nestedField(u.Object, d.path, d.query)
 	if err != nil {
 		return nil, err
 	}
 	if !ok {
-		return nil, errors.New("not found")
+		return nil, ErrValueNotFound
 	}
 
 	outputName := d.outputName
@@ -242,21 +323,25 @@ func (d *stringOfMapDefinition) Apply(u *unstructured.Unstructured) (Value, erro
 type sliceOfMapsFromPathDefinition struct {
 	outputName  string
 	path        []string
+	query       *jsonpath.JSONPath
 	sourceKey   string
 	sourceValue string
+	bindAs      BindingType
 }
 
 var _ Definition = (*sliceOfMapsFromPathDefinition)(nil)
 
 func (d *sliceOfMapsFromPathDefinition) GetPath() []string { return d.path[0 : len(d.path)-1] }
 
+func (d *sliceOfMapsFromPathDefinition) GetBindAs() BindingType { return d.bindAs }
+
 func (d *sliceOfMapsFromPathDefinition) Apply(u *unstructured.Unstructured) (Value, error) {
-	val, ok, err := unstructured.NestedSlice(u.Object, d.path...)
+	val, ok, err := nestedSlice(u.Object, d.path, d.query)
 	if err != nil {
 		return nil, err
 	}
 	if !ok {
-		return nil, errors.New("not found")
+		return nil, ErrValueNotFound
 	}
 
 	v := make(map[string]interface{})
@@ -275,20 +360,24 @@ func (d *sliceOfMapsFromPathDefinition) Apply(u *unstructured.Unstructured) (Val
 type sliceOfStringsFromPathDefinition struct {
 	outputName  string
 	path        []string
+	query       *jsonpath.JSONPath
 	sourceValue string
+	bindAs      BindingType
 }
 
 var _ Definition = (*sliceOfStringsFromPathDefinition)(nil)
 
 func (d *sliceOfStringsFromPathDefinition) GetPath() []string { return d.path[0 : len(d.path)-1] }
 
+func (d *sliceOfStringsFromPathDefinition) GetBindAs() BindingType { return d.bindAs }
+
 func (d *sliceOfStringsFromPathDefinition) Apply(u *unstructured.Unstructured) (Value, error) {
-	val, ok, err := unstructured.NestedSlice(u.Object, d.path...)
+	val, ok, err := nestedSlice(u.Object, d.path, d.query)
 	if err != nil {
 		return nil, err
 	}
 	if !ok {
-		return nil, errors.New("not found")
+		return nil, ErrValueNotFound
 	}
 
 	v := make([]interface{}, 0, len(val))
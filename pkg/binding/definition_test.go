@@ -216,6 +216,56 @@ func TestMapFromSecretDataField(t *testing.T) {
 	require.Equal(t, v, val.Get())
 }
 
+func TestStringFromSecretDataField(t *testing.T) {
+	f := mocks.NewFake(t, "test-namespace")
+	f.AddMockedUnstructuredSecret("dbCredentials-secret")
+	u := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"namespace": "test-namespace",
+			},
+			"status": map[string]interface{}{
+				"dbCredentials": "dbCredentials-secret",
+			},
+		},
+	}
+
+	testCases := []struct {
+		description   string
+		outputName    string
+		expectedValue interface{}
+	}{
+		{
+			description: "outputName informed",
+			outputName:  "user",
+			expectedValue: map[string]interface{}{
+				"user": "user",
+			},
+		},
+		{
+			description: "outputName empty",
+			expectedValue: map[string]interface{}{
+				"username": "user",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			d := &stringFromDataFieldDefinition{
+				kubeClient: f.FakeDynClient(),
+				objectType: secretObjectType,
+				outputName: tc.outputName,
+				path:       []string{"status", "dbCredentials"},
+				sourceKey:  "username",
+			}
+			val, err := d.Apply(u)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedValue, val.Get())
+		})
+	}
+}
+
 func TestMapFromConfigMapDataField(t *testing.T) {
 	f := mocks.NewFake(t, "test-namespace")
 	f.AddMockedUnstructuredConfigMap("dbCredentials-configMap")
@@ -0,0 +1,45 @@
+package binding
+
+import "strings"
+
+// Description describes the value a binding annotation declares, without collecting it.
+type Description struct {
+	// Name is the name the collected value is exposed as; for maps read from a Secret or ConfigMap,
+	// the keys of the referenced resource are exposed instead.
+	Name string
+	// Path is the location of the value in the service resource, e.g. "status.dbCredentials".
+	Path string
+	// ElementType is the type of the value found at Path.
+	ElementType string
+	// ObjectType indicates whether the value is read from the service resource itself or from a
+	// Secret or ConfigMap referenced by it.
+	ObjectType string
+	// BindAs is the medium the value is delivered through, or empty for the Service Binding default.
+	BindAs BindingType
+	// Profile is the credential profile the value belongs to, or empty if shared by all profiles.
+	Profile string
+}
+
+// Describe returns the description of the value declared by the given binding annotation.
+func Describe(annotationName string, annotationValue string) (*Description, error) {
+	var profile string
+	if p, name, ok := parseProfileAnnotation(annotationName); ok {
+		profile, annotationName = p, profileDefinitionAnnotation(name, annotationValue)
+	}
+	builder := &annotationBackedDefinitionBuilder{
+		name:  annotationName,
+		value: annotationValue,
+	}
+	outputName, mod, err := builder.parse()
+	if err != nil {
+		return nil, err
+	}
+	return &Description{
+		Name:        outputName,
+		Path:        strings.Join(mod.path, "."),
+		ElementType: string(mod.elementType),
+		ObjectType:  string(mod.objectType),
+		BindAs:      mod.bindAs,
+		Profile:     profile,
+	}, nil
+}
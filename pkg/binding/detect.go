@@ -0,0 +1,145 @@
+package binding
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// DetectableFields are the well-known field names looked for in services without binding metadata,
+// both in the service resource and in the Secrets it references.
+var DetectableFields = []string{"host", "port", "uri", "username", "password", "database", "ca.crt"}
+
+// secretsGVR is the resource Secrets referenced by detected services are read from.
+var secretsGVR = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+
+// DetectAnnotations inspects the status and spec of the given service, in this order, looking for
+// DetectableFields either holding a value or present in a Secret referenced by a field whose name
+// mentions a secret or credentials, and returns the binding annotations collecting those. The first
+// field found for each name wins.
+func DetectAnnotations(kubeClient dynamic.Interface, obj *unstructured.Unstructured) (map[string]string, error) {
+	d := &detector{
+		kubeClient: kubeClient,
+		namespace:  obj.GetNamespace(),
+		anns:       make(map[string]string),
+		detected:   make(map[string]bool),
+	}
+	for _, root := range []string{"status", "spec"} {
+		if m, ok := obj.Object[root].(map[string]interface{}); ok {
+			if err := d.walk(m, []string{root}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return d.anns, nil
+}
+
+type detector struct {
+	kubeClient dynamic.Interface
+	namespace  string
+	anns       map[string]string
+	detected   map[string]bool
+}
+
+func (d *detector) walk(m map[string]interface{}, path []string) error {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		// paths are dot separated and can't address field names containing separators
+		if strings.ContainsAny(k, ".=,{}") {
+			continue
+		}
+		p := append(append([]string{}, path...), k)
+		switch v := m[k].(type) {
+		case map[string]interface{}:
+			if err := d.walk(v, p); err != nil {
+				return err
+			}
+		case string:
+			if isSecretReference(path, k) {
+				if err := d.detectSecret(v, p); err != nil {
+					return err
+				}
+				continue
+			}
+			d.detect(strings.ToLower(k), fmt.Sprintf("path={.%s}", strings.Join(p, ".")))
+		case int64, float64:
+			d.detect(strings.ToLower(k), fmt.Sprintf("path={.%s}", strings.Join(p, ".")))
+		}
+	}
+	return nil
+}
+
+// detect registers the annotation for the given field name, if it is detectable and hasn't been
+// detected yet.
+func (d *detector) detect(name string, value string) {
+	if d.detected[name] || !isDetectableField(name) {
+		return
+	}
+	d.detected[name] = true
+	d.anns[AnnotationPrefix+"/"+name] = value
+}
+
+// detectSecret looks for detectable fields in the Secret with the given name, referenced at path.
+func (d *detector) detectSecret(name string, path []string) error {
+	if len(name) == 0 || d.kubeClient == nil {
+		return nil
+	}
+	secret, err := d.kubeClient.Resource(secretsGVR).Namespace(d.namespace).
+		Get(context.TODO(), name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	data, _, err := unstructured.NestedMap(secret.Object, "data")
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		// sourceKey values can't contain the separators of the annotation syntax
+		if strings.ContainsAny(k, "=,") {
+			continue
+		}
+		d.detect(k, fmt.Sprintf("path={.%s},objectType=Secret,sourceKey=%s", strings.Join(path, "."), k))
+	}
+	return nil
+}
+
+// isSecretReference evaluates whether the given field likely holds the name of a Secret, either
+// because its name mentions a secret or credentials, or because it is the name field of an object
+// doing so.
+func isSecretReference(path []string, field string) bool {
+	name := strings.ToLower(field)
+	if name == "name" && len(path) > 0 {
+		name = strings.ToLower(path[len(path)-1])
+	}
+	return strings.Contains(name, "secret") || strings.Contains(name, "credential")
+}
+
+func isDetectableField(name string) bool {
+	for _, f := range DetectableFields {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package binding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/redhat-developer/service-binding-operator/test/mocks"
+)
+
+func TestDetectAnnotations(t *testing.T) {
+	ns := "detect"
+	f := mocks.NewFake(t, ns)
+	f.AddNamespacedMockedSecret("db-credentials", ns, map[string][]byte{
+		"username": []byte("user"),
+		"password": []byte("password"),
+		"ca.crt":   []byte("certificate"),
+		"host":     []byte("db.example.com"),
+		"extra":    []byte("ignored"),
+	})
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"namespace": ns, "name": "db"},
+		"spec": map[string]interface{}{
+			"database": "inventory",
+			"host":     "ignored.example.com",
+			"connection": map[string]interface{}{
+				"port": int64(5432),
+			},
+			"tls.options": map[string]interface{}{"uri": "ignored"},
+		},
+		"status": map[string]interface{}{
+			"address": map[string]interface{}{
+				"host": "10.0.0.1",
+			},
+			"dbCredentials": "db-credentials",
+			"adminSecret": map[string]interface{}{
+				"name": "missing-secret",
+			},
+			"replicas": int64(3),
+		},
+	}}
+
+	anns, err := DetectAnnotations(f.FakeDynClient(), obj)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"service.binding/host":     "path={.status.address.host}",
+		"service.binding/ca.crt":   "path={.status.dbCredentials},objectType=Secret,sourceKey=ca.crt",
+		"service.binding/username": "path={.status.dbCredentials},objectType=Secret,sourceKey=username",
+		"service.binding/password": "path={.status.dbCredentials},objectType=Secret,sourceKey=password",
+		"service.binding/database": "path={.spec.database}",
+		"service.binding/port":     "path={.spec.connection.port}",
+	}, anns)
+
+	for k, v := range anns {
+		_, err := Describe(k, v)
+		require.NoError(t, err, k)
+	}
+}
+
+func TestDetectAnnotationsNothingFound(t *testing.T) {
+	f := mocks.NewFake(t, "detect")
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"namespace": "detect", "name": "db"},
+		"spec":     map[string]interface{}{"replicas": int64(1)},
+	}}
+
+	anns, err := DetectAnnotations(f.FakeDynClient(), obj)
+	require.NoError(t, err)
+	require.Empty(t, anns)
+}
@@ -1,19 +1,41 @@
 package binding
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"regexp"
+	"strconv"
 	"strings"
+
+	"github.com/redhat-developer/service-binding-operator/pkg/envvars"
+	"go.starlark.net/starlark"
+	"k8s.io/client-go/util/jsonpath"
 )
 
 type model struct {
-	path        []string
+	path []string
+	// query evaluates the path when it can't be expressed as a sequence of field names, for example
+	// when it holds filters or indexes; nil otherwise.
+	query       *jsonpath.JSONPath
 	elementType elementType
 	objectType  objectType
 	sourceKey   string
 	sourceValue string
 	bindAs      BindingType
+	// optional indicates the value might not be present in the resource.
+	optional bool
+	// defaultValue is used in the case the value isn't present in the resource; nil means no
+	// default value has been informed.
+	defaultValue *string
+	// script is the source of the script computing the values, for the script element type.
+	script string
+	// program is the compiled script, kept so that it isn't compiled again by the definition.
+	program *starlark.Program
+	// arrays configures how the slices found in the value are rendered; an empty format means the
+	// one configured in the Service Binding should be used.
+	arrays envvars.ArrayRendering
 }
 
 func (m *model) isStringElementType() bool {
@@ -50,7 +72,8 @@ func newModel(annotationValue string) (*model, error) {
 	// its length should be even, since from this point on is assumed a sequence of key and value
 	// pairs as model source
 	if len(split)%2 != 0 {
-		m := fmt.Sprintf("invalid input, odd number of tokens: %q", split)
+		m := fmt.Sprintf("invalid input, odd number of tokens: %q; values containing '=' or ',' "+
+			"should be declared through a %s annotation", split, JSONAnnotationPrefix)
 		return nil, errors.New(m)
 	}
 
@@ -65,6 +88,168 @@ func newModel(annotationValue string) (*model, error) {
 		raw[k] = v
 	}
 
+	return newModelFromRaw(raw, annotationValue)
+}
+
+// newScriptModel creates a model for the given script, ensuring it is syntactically valid.
+func newScriptModel(name string, script string) (*model, error) {
+	if len(name) == 0 {
+		return nil, fmt.Errorf("%s annotations require a name", ScriptAnnotationPrefix)
+	}
+	program, err := compileScript(name, script)
+	if err != nil {
+		return nil, err
+	}
+	return &model{
+		elementType: scriptElementType,
+		objectType:  stringObjectType,
+		script:      script,
+		program:     program,
+	}, nil
+}
+
+// jsonModel is the structured representation of a binding model, accepted by annotations prefixed
+// by JSONAnnotationPrefix; its fields have the same meaning as the keys of the legacy syntax, with
+// Delivery standing for bindAs.
+type jsonModel struct {
+	Path        string  `json:"path"`
+	ObjectType  string  `json:"objectType,omitempty"`
+	ElementType string  `json:"elementType,omitempty"`
+	SourceKey   string  `json:"sourceKey,omitempty"`
+	SourceValue string  `json:"sourceValue,omitempty"`
+	Delivery    string  `json:"delivery,omitempty"`
+	Optional    *bool   `json:"optional,omitempty"`
+	Default     *string `json:"default,omitempty"`
+	// ArraySeparator can hold "," here, unlike in the legacy syntax.
+	ArrayFormat    string `json:"arrayFormat,omitempty"`
+	ArraySeparator string `json:"arraySeparator,omitempty"`
+}
+
+// newModelFromJSON creates a model from a JSON object; unknown fields and values of unexpected
+// types are rejected.
+func newModelFromJSON(annotationValue string) (*model, error) {
+	dec := json.NewDecoder(strings.NewReader(annotationValue))
+	dec.DisallowUnknownFields()
+
+	var jm jsonModel
+	if err := dec.Decode(&jm); err != nil {
+		return nil, fmt.Errorf("invalid JSON binding definition: %v", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("invalid JSON binding definition: unexpected data after object")
+	}
+	if err := jm.validate(); err != nil {
+		return nil, fmt.Errorf("invalid JSON binding definition: %v", err)
+	}
+
+	raw := map[modelKey]string{
+		pathModelKey:        jm.Path,
+		objectTypeModelKey:  jm.ObjectType,
+		sourceKeyModelKey:   jm.SourceKey,
+		sourceValueModelKey: jm.SourceValue,
+		bindAsModelKey:      jm.Delivery,
+	}
+	// elementType is inferred from the other keys when absent, so it is only set when informed
+	if len(jm.ElementType) > 0 {
+		raw[elementTypeModelKey] = jm.ElementType
+	}
+	if jm.Optional != nil {
+		raw[optionalModelKey] = strconv.FormatBool(*jm.Optional)
+	}
+	if jm.Default != nil {
+		raw[defaultModelKey] = *jm.Default
+	}
+	if len(jm.ArrayFormat) > 0 {
+		raw[arrayFormatModelKey] = jm.ArrayFormat
+	}
+	if len(jm.ArraySeparator) > 0 {
+		raw[arraySeparatorModelKey] = jm.ArraySeparator
+	}
+
+	m, err := newModelFromRaw(raw, annotationValue)
+	if err != nil {
+		return nil, err
+	}
+	// unlike the legacy syntax, the path is evaluated as a JSONPath expression, so filters and keys
+	// holding dots (escaped as in "{.data.tls\.crt}") can be used
+	if m.path, m.query, err = parseJSONPath(jm.Path); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// validate checks the presence of the required fields and the values of the fields accepting a
+// fixed set of values, returning an error naming the offending field; objectType and elementType
+// are checked against the definition registry when the definition is built.
+func (jm *jsonModel) validate() error {
+	if len(jm.Path) == 0 {
+		return errors.New("path is required")
+	}
+	switch BindingType(jm.Delivery) {
+	case "", TypeVolumeMount, bindingTypeVolumeAlias, TypeEnvVar, bindingTypeEnvVarAlias:
+	default:
+		return fmt.Errorf("delivery has invalid value: %q", jm.Delivery)
+	}
+	if len(jm.ArrayFormat) > 0 {
+		if _, err := envvars.ParseArrayFormat(jm.ArrayFormat); err != nil {
+			return fmt.Errorf("arrayFormat has invalid value: %q", jm.ArrayFormat)
+		}
+	}
+	if elementType(jm.ElementType) == sliceOfMapsElementType {
+		if len(jm.SourceKey) == 0 {
+			return fmt.Errorf("sourceKey is required for elementType %q", sliceOfMapsElementType)
+		}
+		if len(jm.SourceValue) == 0 {
+			return fmt.Errorf("sourceValue is required for elementType %q", sliceOfMapsElementType)
+		}
+	}
+	return nil
+}
+
+// parseJSONPath parses the given JSONPath expression, returning the names of the fields it walks
+// through; a query evaluating the expression is also returned when it holds anything other than
+// field names.
+func parseJSONPath(path string) ([]string, *jsonpath.JSONPath, error) {
+	p, err := jsonpath.Parse(string(pathModelKey), path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("path has invalid syntax: %q: %v", path, err)
+	}
+	var list *jsonpath.ListNode
+	if len(p.Root.Nodes) == 1 {
+		list, _ = p.Root.Nodes[0].(*jsonpath.ListNode)
+	}
+	if list == nil {
+		return nil, nil, fmt.Errorf("path has invalid syntax: %q", path)
+	}
+
+	var fields []string
+	plain := true
+	for _, n := range list.Nodes {
+		if f, ok := n.(*jsonpath.FieldNode); ok {
+			if len(f.Value) > 0 {
+				fields = append(fields, f.Value)
+			}
+			continue
+		}
+		plain = false
+	}
+	if len(fields) == 0 {
+		return nil, nil, fmt.Errorf("path has invalid syntax: %q", path)
+	}
+	if plain {
+		return fields, nil, nil
+	}
+
+	query := jsonpath.New(string(pathModelKey)).AllowMissingKeys(true)
+	if err := query.Parse(path); err != nil {
+		return nil, nil, fmt.Errorf("path has invalid syntax: %q: %v", path, err)
+	}
+	return fields, query, nil
+}
+
+// newModelFromRaw validates the given key and value pairs, extracted from annotationValue, and
+// creates a model filling in defaults for the absent keys.
+func newModelFromRaw(raw map[modelKey]string, annotationValue string) (*model, error) {
 	// assert PathModelKey is present
 	path, found := raw[pathModelKey]
 	if !found {
@@ -120,6 +305,52 @@ func newModel(annotationValue string) (*model, error) {
 		sourceValue = ""
 	}
 
+	// bindAs is optional; an empty value indicates the delivery configured in the Service Binding
+	// should be used
+	var bindAs BindingType
+	if rawBindAs, found := raw[bindAsModelKey]; found {
+		switch BindingType(rawBindAs) {
+		case TypeVolumeMount, bindingTypeVolumeAlias:
+			bindAs = TypeVolumeMount
+		case TypeEnvVar, bindingTypeEnvVarAlias:
+			bindAs = TypeEnvVar
+		case "":
+		default:
+			return nil, fmt.Errorf("bindAs has invalid value: %q", rawBindAs)
+		}
+	}
+
+	// values are required unless explicitly marked as optional
+	var optional bool
+	if rawOptional, found := raw[optionalModelKey]; found {
+		var err error
+		if optional, err = strconv.ParseBool(rawOptional); err != nil {
+			return nil, fmt.Errorf("optional has invalid value: %q", rawOptional)
+		}
+	}
+
+	// default is kept as a pointer, since an empty string is a valid default value
+	var defaultValue *string
+	if rawDefault, found := raw[defaultModelKey]; found {
+		defaultValue = &rawDefault
+	}
+
+	// arrayFormat is optional; an empty value indicates the format configured in the Service
+	// Binding should be used
+	var arrays envvars.ArrayRendering
+	if rawArrayFormat, found := raw[arrayFormatModelKey]; found && len(rawArrayFormat) > 0 {
+		var err error
+		if arrays.Format, err = envvars.ParseArrayFormat(rawArrayFormat); err != nil {
+			return nil, fmt.Errorf("arrayFormat has invalid value: %q", rawArrayFormat)
+		}
+	}
+	if sep, found := raw[arraySeparatorModelKey]; found && len(sep) > 0 {
+		if arrays.Format != envvars.ArrayFormatJoin {
+			return nil, fmt.Errorf("arraySeparator requires arrayFormat to be %q", envvars.ArrayFormatJoin)
+		}
+		arrays.Separator = sep
+	}
+
 	// ensure an error is returned if not all required information is available for sliceOfMaps
 	// element type
 	if eltType == sliceOfMapsElementType && (len(sourceValue) == 0 || len(sourceKey) == 0) {
@@ -129,11 +360,14 @@ func newModel(annotationValue string) (*model, error) {
 	pathParts := strings.Split(path, ".")
 
 	return &model{
-		path:        pathParts,
-		elementType: eltType,
-		objectType:  objType,
-		sourceValue: sourceValue,
-		sourceKey:   sourceKey,
-		bindAs:      TypeEnvVar,
+		path:         pathParts,
+		elementType:  eltType,
+		objectType:   objType,
+		sourceValue:  sourceValue,
+		sourceKey:    sourceKey,
+		bindAs:       bindAs,
+		optional:     optional,
+		defaultValue: defaultValue,
+		arrays:       arrays,
 	}, nil
 }
@@ -0,0 +1,164 @@
+package binding
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// Profile supplies the binding annotations for a kind of service whose operator doesn't provide
+// binding metadata.
+type Profile struct {
+	Group string `json:"group"`
+	// Version restricts the profile to a single version of the kind; when empty the profile applies
+	// to every version.
+	Version string `json:"version,omitempty"`
+	Kind    string `json:"kind"`
+	// Annotations are the binding annotations used when the service has none; they may include
+	// ProviderAnnotation.
+	Annotations map[string]string `json:"annotations"`
+}
+
+// validate ensures the profile identifies a kind and only carries valid binding annotations.
+func (p *Profile) validate() error {
+	if len(p.Kind) == 0 {
+		return fmt.Errorf("profile for group %q has no kind", p.Group)
+	}
+	if len(p.Annotations) == 0 {
+		return fmt.Errorf("profile for %s has no annotations", p.groupVersionKind())
+	}
+	for k, v := range p.Annotations {
+		if k == ProviderAnnotation {
+			if err := validateProviderEndpoint(v); err != nil {
+				return fmt.Errorf("profile for %s has invalid binding provider: %v", p.groupVersionKind(), err)
+			}
+			continue
+		}
+		if !IsBindingAnnotation(k) {
+			return fmt.Errorf("profile for %s has invalid annotation name %q", p.groupVersionKind(), k)
+		}
+		if _, err := Describe(k, v); err != nil {
+			return fmt.Errorf("profile for %s has invalid annotation %q: %v", p.groupVersionKind(), k, err)
+		}
+	}
+	return nil
+}
+
+func (p *Profile) groupVersionKind() schema.GroupVersionKind {
+	return schema.GroupVersionKind{Group: p.Group, Version: p.Version, Kind: p.Kind}
+}
+
+// ParseProfiles parses and validates a YAML (or JSON) list of profiles.
+func ParseProfiles(data []byte) ([]Profile, error) {
+	var profiles []Profile
+	if err := yaml.UnmarshalStrict(data, &profiles); err != nil {
+		return nil, err
+	}
+	for i := range profiles {
+		if err := profiles[i].validate(); err != nil {
+			return nil, err
+		}
+	}
+	return profiles, nil
+}
+
+// ProfileRegistry holds the profiles applied to services without binding metadata; it is safe for
+// concurrent use.
+//
+// Profiles are organized in sources: the builtin profiles the registry has been created with, and
+// named sources set at runtime. The profile found in the last source, in lexical order, wins over
+// the builtin ones; within a source, a profile for the exact version wins over a profile for every
+// version.
+type ProfileRegistry struct {
+	mu      sync.RWMutex
+	builtin []Profile
+	sources map[string][]Profile
+}
+
+// NewProfileRegistry returns a registry holding the given builtin profiles.
+func NewProfileRegistry(builtin []Profile) *ProfileRegistry {
+	return &ProfileRegistry{builtin: builtin, sources: make(map[string][]Profile)}
+}
+
+// DefaultProfileRegistry is the registry consulted by the operator, holding BuiltinProfiles.
+var DefaultProfileRegistry = NewProfileRegistry(BuiltinProfiles)
+
+// SetSource replaces the profiles of the given source; an empty list removes the source.
+func (r *ProfileRegistry) SetSource(source string, profiles []Profile) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(profiles) == 0 {
+		delete(r.sources, source)
+		return
+	}
+	r.sources[source] = profiles
+}
+
+// ReplaceSources replaces all the sources set at runtime with the given ones.
+func (r *ProfileRegistry) ReplaceSources(sources map[string][]Profile) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sources = make(map[string][]Profile, len(sources))
+	for k, v := range sources {
+		if len(v) > 0 {
+			r.sources[k] = v
+		}
+	}
+}
+
+// Lookup returns the profile applying to the given kind.
+func (r *ProfileRegistry) Lookup(gvk schema.GroupVersionKind) (*Profile, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.sources))
+	for k := range r.sources {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	for i := len(names) - 1; i >= 0; i-- {
+		if p := selectProfile(r.sources[names[i]], gvk); p != nil {
+			return p, true
+		}
+	}
+	if p := selectProfile(r.builtin, gvk); p != nil {
+		return p, true
+	}
+	return nil, false
+}
+
+// Annotations returns a copy of the annotations of the profile applying to the given kind, or nil
+// if there's none.
+func (r *ProfileRegistry) Annotations(gvk schema.GroupVersionKind) map[string]string {
+	p, ok := r.Lookup(gvk)
+	if !ok {
+		return nil
+	}
+	anns := make(map[string]string, len(p.Annotations))
+	for k, v := range p.Annotations {
+		anns[k] = v
+	}
+	return anns
+}
+
+// selectProfile returns the profile matching the given kind, preferring the one for its version.
+func selectProfile(profiles []Profile, gvk schema.GroupVersionKind) *Profile {
+	var found *Profile
+	for i := range profiles {
+		p := &profiles[i]
+		if p.Group != gvk.Group || p.Kind != gvk.Kind {
+			continue
+		}
+		if p.Version == gvk.Version {
+			return p
+		}
+		if len(p.Version) == 0 && found == nil {
+			found = p
+		}
+	}
+	return found
+}
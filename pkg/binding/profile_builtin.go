@@ -0,0 +1,84 @@
+package binding
+
+// BuiltinProfiles are the profiles shipped with the operator, for popular operators not providing
+// binding metadata themselves.
+var BuiltinProfiles = []Profile{
+	{
+		// Strimzi
+		Group:   "kafka.strimzi.io",
+		Version: "v1beta2",
+		Kind:    "Kafka",
+		Annotations: map[string]string{
+			"service.binding/bootstrapServers": "path={.status.listeners},elementType=sliceOfMaps,sourceKey=name,sourceValue=bootstrapServers",
+		},
+	},
+	{
+		Group:   "kafka.strimzi.io",
+		Version: "v1beta1",
+		Kind:    "Kafka",
+		Annotations: map[string]string{
+			"service.binding/bootstrapServers": "path={.status.listeners},elementType=sliceOfMaps,sourceKey=type,sourceValue=bootstrapServers",
+		},
+	},
+	{
+		Group: "kafka.strimzi.io",
+		Kind:  "KafkaUser",
+		Annotations: map[string]string{
+			"service.binding/username":    "path={.status.username}",
+			"service.binding/credentials": "path={.status.secret},objectType=Secret",
+		},
+	},
+	{
+		// RabbitMQ Cluster Operator
+		Group: "rabbitmq.com",
+		Kind:  "RabbitmqCluster",
+		Annotations: map[string]string{
+			"service.binding/credentials": "path={.status.binding.name},objectType=Secret",
+		},
+	},
+	{
+		// MongoDB Community Operator
+		Group: "mongodbcommunity.mongodb.com",
+		Kind:  "MongoDBCommunity",
+		Annotations: map[string]string{
+			"service.binding/uri": "path={.status.mongoUri}",
+		},
+	},
+	{
+		// CloudNativePG
+		Group: "postgresql.cnpg.io",
+		Kind:  "Cluster",
+		Annotations: map[string]string{
+			"service.binding/host":        "path={.status.writeService}",
+			"service.binding/database":    "path={.spec.bootstrap.initdb.database},default=app",
+			"service.binding/credentials": "path={.spec.bootstrap.initdb.secret.name},objectType=Secret,optional=true",
+		},
+	},
+	{
+		// Percona Operator for MySQL; the Secret referred by the cluster only holds the credentials
+		// of system users, such as root, so only the host is bound
+		Group: "pxc.percona.com",
+		Kind:  "PerconaXtraDBCluster",
+		Annotations: map[string]string{
+			"service.binding/host": "path={.status.host}",
+		},
+	},
+	{
+		// Percona Operator for MongoDB; as above, the users Secret only holds system users
+		Group: "psmdb.percona.com",
+		Kind:  "PerconaServerMongoDB",
+		Annotations: map[string]string{
+			"service.binding/host": "path={.status.host}",
+		},
+	},
+	{
+		// Redis Enterprise Operator
+		Group: "app.redislabs.com",
+		Kind:  "RedisEnterpriseDatabase",
+		Annotations: map[string]string{
+			"service.binding.json/host": `{"path": "{.status.internalEndpoints[0].host}"}`,
+			"service.binding.json/port": `{"path": "{.status.internalEndpoints[0].port}"}`,
+			"service.binding/password":  "path={.spec.databaseSecretName},objectType=Secret,sourceKey=password,optional=true",
+		},
+	},
+}
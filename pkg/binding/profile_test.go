@@ -0,0 +1,176 @@
+package binding
+
+import (
+	"testing"
+
+	"github.com/redhat-developer/service-binding-operator/pkg/testutils"
+	"github.com/redhat-developer/service-binding-operator/test/mocks"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestBuiltinProfiles(t *testing.T) {
+	for _, p := range BuiltinProfiles {
+		p := p
+		t.Run(p.groupVersionKind().String(), func(t *testing.T) {
+			require.NoError(t, p.validate())
+		})
+	}
+}
+
+func TestBuiltinRedisEnterpriseProfile(t *testing.T) {
+	f := mocks.NewFake(t, "test")
+	f.AddNamespacedMockedSecret("redb-cache", "test", map[string][]byte{"password": []byte("s3cr3t")})
+	redb := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "app.redislabs.com/v1alpha1",
+		"kind":       "RedisEnterpriseDatabase",
+		"metadata":   map[string]interface{}{"name": "cache", "namespace": "test"},
+		"spec":       map[string]interface{}{"databaseSecretName": "redb-cache"},
+		"status": map[string]interface{}{
+			"internalEndpoints": []interface{}{
+				map[string]interface{}{"host": "redis-12000.rec.test.svc", "port": int64(12000)},
+			},
+		},
+	}}
+
+	anns := NewProfileRegistry(BuiltinProfiles).Annotations(redb.GroupVersionKind())
+	require.NotEmpty(t, anns)
+
+	data := make(map[string]interface{})
+	for k, v := range anns {
+		h, err := NewSpecHandler(f.FakeDynClient(), k, v, redb, testutils.BuildTestRESTMapper(), nil)
+		require.NoError(t, err)
+		r, err := h.Handle()
+		require.NoError(t, err)
+		for name, value := range r.Data {
+			data[name] = value
+		}
+	}
+	require.Equal(t, map[string]interface{}{
+		"host":     "redis-12000.rec.test.svc",
+		"port":     "12000",
+		"password": "s3cr3t",
+	}, data)
+}
+
+func TestParseProfiles(t *testing.T) {
+	t.Run("valid profiles", func(t *testing.T) {
+		profiles, err := ParseProfiles([]byte(`
+- group: cache.example.com
+  kind: Redis
+  annotations:
+    service.binding/host: path={.status.host}
+    service.binding.json/password: '{"path": "{.spec.passwordSecret}", "objectType": "Secret", "sourceKey": "password"}'
+- group: cache.example.com
+  version: v1beta1
+  kind: Redis
+  annotations:
+    service.binding/host: path={.status.address}
+- group: cache.example.com
+  kind: Memcached
+  annotations:
+    service.binding.provider: https://provider.example.com/memcached
+`))
+		require.NoError(t, err)
+		require.Equal(t, []Profile{
+			{
+				Group: "cache.example.com",
+				Kind:  "Redis",
+				Annotations: map[string]string{
+					"service.binding/host":          "path={.status.host}",
+					"service.binding.json/password": `{"path": "{.spec.passwordSecret}", "objectType": "Secret", "sourceKey": "password"}`,
+				},
+			},
+			{
+				Group:   "cache.example.com",
+				Version: "v1beta1",
+				Kind:    "Redis",
+				Annotations: map[string]string{
+					"service.binding/host": "path={.status.address}",
+				},
+			},
+			{
+				Group: "cache.example.com",
+				Kind:  "Memcached",
+				Annotations: map[string]string{
+					"service.binding.provider": "https://provider.example.com/memcached",
+				},
+			},
+		}, profiles)
+	})
+
+	invalid := map[string]string{
+		"not a list":              `group: cache.example.com`,
+		"unknown field":           `[{group: cache.example.com, kind: Redis, annotation: {service.binding/host: "path={.status.host}"}}]`,
+		"missing kind":            `[{group: cache.example.com, annotations: {service.binding/host: "path={.status.host}"}}]`,
+		"missing annotations":     `[{group: cache.example.com, kind: Redis}]`,
+		"non binding annotation":  `[{group: cache.example.com, kind: Redis, annotations: {example.com/host: "path={.status.host}"}}]`,
+		"invalid annotation path": `[{group: cache.example.com, kind: Redis, annotations: {service.binding/host: "path={.status.host"}}]`,
+		"invalid provider":        `[{group: cache.example.com, kind: Redis, annotations: {service.binding.provider: "provider.example.com"}}]`,
+	}
+	for name, data := range invalid {
+		data := data
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseProfiles([]byte(data))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestProfileRegistry(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "cache.example.com", Version: "v1beta1", Kind: "Redis"}
+	anyVersion := Profile{
+		Group:       gvk.Group,
+		Kind:        gvk.Kind,
+		Annotations: map[string]string{"service.binding/host": "path={.status.host}"},
+	}
+	exactVersion := Profile{
+		Group:       gvk.Group,
+		Version:     gvk.Version,
+		Kind:        gvk.Kind,
+		Annotations: map[string]string{"service.binding/host": "path={.status.address}"},
+	}
+
+	t.Run("unknown kind", func(t *testing.T) {
+		r := NewProfileRegistry([]Profile{anyVersion})
+		_, found := r.Lookup(schema.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: "Memcached"})
+		require.False(t, found)
+		require.Nil(t, r.Annotations(schema.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: "Memcached"}))
+	})
+
+	t.Run("exact version wins over any version", func(t *testing.T) {
+		r := NewProfileRegistry([]Profile{anyVersion, exactVersion})
+		require.Equal(t, exactVersion.Annotations, r.Annotations(gvk))
+		other := gvk
+		other.Version = "v1"
+		require.Equal(t, anyVersion.Annotations, r.Annotations(other))
+	})
+
+	t.Run("sources win over builtin profiles", func(t *testing.T) {
+		r := NewProfileRegistry([]Profile{exactVersion})
+		r.SetSource("a", []Profile{anyVersion})
+		require.Equal(t, anyVersion.Annotations, r.Annotations(gvk))
+
+		r.SetSource("a", nil)
+		require.Equal(t, exactVersion.Annotations, r.Annotations(gvk))
+	})
+
+	t.Run("last source wins", func(t *testing.T) {
+		r := NewProfileRegistry(nil)
+		r.ReplaceSources(map[string][]Profile{
+			"b": {exactVersion},
+			"a": {anyVersion},
+		})
+		require.Equal(t, exactVersion.Annotations, r.Annotations(gvk))
+
+		r.ReplaceSources(map[string][]Profile{"a": {anyVersion}})
+		require.Equal(t, anyVersion.Annotations, r.Annotations(gvk))
+	})
+
+	t.Run("returned annotations are a copy", func(t *testing.T) {
+		r := NewProfileRegistry([]Profile{exactVersion})
+		r.Annotations(gvk)["service.binding/host"] = "path={.status.other}"
+		require.Equal(t, "path={.status.address}", r.Annotations(gvk)["service.binding/host"])
+	})
+}
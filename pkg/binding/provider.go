@@ -0,0 +1,268 @@
+package binding
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ProviderAnnotation is the annotation naming the HTTP(S) endpoint of the binding provider
+	// computing additional binding values for a service.
+	ProviderAnnotation = "service.binding.provider"
+	// maxProviderResponseSize is the maximum size of a binding provider response body.
+	maxProviderResponseSize = 1 << 20
+)
+
+// ProviderApplication identifies the application a Service Binding binds to.
+type ProviderApplication struct {
+	Group    string `json:"group"`
+	Version  string `json:"version"`
+	Resource string `json:"resource"`
+	Name     string `json:"name"`
+}
+
+// ProviderBinding describes the Service Binding a binding provider is computing values for.
+type ProviderBinding struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// ServiceID is the id the service is referred to by in the Service Binding, if any.
+	ServiceID string `json:"serviceId,omitempty"`
+	// Application is the application being bound, if any.
+	Application *ProviderApplication `json:"application,omitempty"`
+}
+
+// ProviderRequest is the payload POSTed to binding providers.
+type ProviderRequest struct {
+	// Service is the service resource being bound.
+	Service map[string]interface{} `json:"service"`
+	// Binding is the Service Binding the service is bound by.
+	Binding ProviderBinding `json:"binding"`
+}
+
+// BindingProvider computes binding values for a service through an external endpoint.
+type BindingProvider interface {
+	// Provide requests the binding values for the given request from the endpoint.
+	Provide(endpoint string, req *ProviderRequest) (map[string]string, error)
+}
+
+// ErrBindingProviderNotAllowed is returned when the endpoint of a binding provider isn't allowed
+// by the operator configuration.
+type ErrBindingProviderNotAllowed string
+
+func (e ErrBindingProviderNotAllowed) Error() string {
+	return fmt.Sprintf("binding provider endpoint %q is not allowed", string(e))
+}
+
+func IsErrBindingProviderNotAllowed(err error) bool {
+	_, ok := err.(ErrBindingProviderNotAllowed)
+	return ok
+}
+
+// HTTPProviderOptions configures the BindingProvider returned by NewHTTPBindingProvider.
+type HTTPProviderOptions struct {
+	// AllowedURLs are the URLs endpoints must be located under: an endpoint has to share the scheme
+	// and host of one of those, and its path has to be equal to or below the path of that one. No
+	// endpoint is allowed when empty.
+	AllowedURLs []string
+	// Timeout is the time limit of each request; no limit is applied when zero.
+	Timeout time.Duration
+	// TLSConfig is the TLS configuration used for HTTPS endpoints; the system defaults are used
+	// when nil.
+	TLSConfig *tls.Config
+	// CacheTTL is the period responses are reused for identical requests, unless the endpoint
+	// states otherwise through the Cache-Control header; responses aren't cached when zero.
+	CacheTTL time.Duration
+}
+
+// providerCacheEntry is a cached binding provider response.
+type providerCacheEntry struct {
+	values  map[string]string
+	expires time.Time
+}
+
+// httpBindingProvider is a BindingProvider POSTing requests as JSON, expecting a JSON object of
+// string values in return.
+type httpBindingProvider struct {
+	client      *http.Client
+	allowedURLs []string
+	cacheTTL    time.Duration
+	now         func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]providerCacheEntry
+}
+
+var _ BindingProvider = (*httpBindingProvider)(nil)
+
+// NewHTTPBindingProvider returns a BindingProvider calling HTTP(S) endpoints.
+func NewHTTPBindingProvider(opts HTTPProviderOptions) BindingProvider {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if opts.TLSConfig != nil {
+		transport.TLSClientConfig = opts.TLSConfig
+	}
+	client := &http.Client{
+		Timeout:   opts.Timeout,
+		Transport: transport,
+		// redirects aren't followed, since their targets might not be allowed
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return &httpBindingProvider{
+		client:      client,
+		allowedURLs: opts.AllowedURLs,
+		cacheTTL:    opts.CacheTTL,
+		now:         time.Now,
+		cache:       make(map[string]providerCacheEntry),
+	}
+}
+
+// validateProviderEndpoint ensures the given endpoint is an absolute HTTP(S) URL.
+func validateProviderEndpoint(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return fmt.Errorf("binding provider endpoint %q is not an absolute HTTP(S) URL", endpoint)
+	}
+	return nil
+}
+
+// isAllowed evaluates whether the given endpoint is located under one of the allowed URLs; the
+// URLs are compared once parsed, so that neither hosts sharing a prefix with an allowed one nor
+// user information can pass for it.
+func (p *httpBindingProvider) isAllowed(endpoint string) bool {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.User != nil {
+		return false
+	}
+	for _, allowed := range p.allowedURLs {
+		if isURLUnder(u, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// isURLUnder evaluates whether u has the scheme and host of base, and a path equal to or below the
+// path of base; paths are compared once cleaned, so that dot segments can't escape base.
+func isURLUnder(u *url.URL, base string) bool {
+	b, err := url.Parse(base)
+	if err != nil || len(b.Host) == 0 || b.User != nil {
+		return false
+	}
+	if !strings.EqualFold(u.Scheme, b.Scheme) || !strings.EqualFold(u.Host, b.Host) {
+		return false
+	}
+	basePath := strings.TrimSuffix(path.Clean("/"+b.Path), "/")
+	p := path.Clean("/" + u.Path)
+	return p == basePath || strings.HasPrefix(p, basePath+"/")
+}
+
+func (p *httpBindingProvider) Provide(endpoint string, req *ProviderRequest) (map[string]string, error) {
+	if err := validateProviderEndpoint(endpoint); err != nil {
+		return nil, err
+	}
+	if !p.isAllowed(endpoint) {
+		return nil, ErrBindingProviderNotAllowed(endpoint)
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(body)
+	key := endpoint + "\x00" + hex.EncodeToString(sum[:])
+
+	if values, ok := p.cached(key); ok {
+		return values, nil
+	}
+
+	resp, err := p.client.Post(endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxProviderResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("binding provider %s returned %s", endpoint, resp.Status)
+	}
+
+	values := make(map[string]string)
+	if err = json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("binding provider %s returned an invalid response: %v", endpoint, err)
+	}
+
+	if ttl := cacheTTL(resp.Header.Get("Cache-Control"), p.cacheTTL); ttl > 0 {
+		p.store(key, values, ttl)
+	}
+
+	return copyValues(values), nil
+}
+
+// cached returns the values cached for the given key, if not expired yet.
+func (p *httpBindingProvider) cached(key string) (map[string]string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry, ok := p.cache[key]
+	if !ok || !p.now().Before(entry.expires) {
+		return nil, false
+	}
+	return copyValues(entry.values), true
+}
+
+// store caches the given values, evicting the expired entries.
+func (p *httpBindingProvider) store(key string, values map[string]string, ttl time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	for k, entry := range p.cache {
+		if !now.Before(entry.expires) {
+			delete(p.cache, k)
+		}
+	}
+	p.cache[key] = providerCacheEntry{values: values, expires: now.Add(ttl)}
+}
+
+// cacheTTL returns the period a response can be cached for according to the given Cache-Control
+// header, or defaultTTL when the header doesn't say.
+func cacheTTL(cacheControl string, defaultTTL time.Duration) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		switch {
+		case directive == "no-store" || directive == "no-cache":
+			return 0
+		case strings.HasPrefix(directive, "max-age="):
+			if seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age=")); err == nil {
+				return time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	return defaultTTL
+}
+
+func copyValues(values map[string]string) map[string]string {
+	out := make(map[string]string, len(values))
+	for k, v := range values {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,252 @@
+package binding
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newProviderRequest() *ProviderRequest {
+	return &ProviderRequest{
+		Service: map[string]interface{}{
+			"apiVersion": "postgresql.baiju.dev/v1alpha1",
+			"kind":       "Database",
+			"metadata": map[string]interface{}{
+				"namespace": "test",
+				"name":      "db",
+			},
+		},
+		Binding: ProviderBinding{
+			Namespace: "test",
+			Name:      "binding",
+			ServiceID: "db",
+			Application: &ProviderApplication{
+				Group:    "apps",
+				Version:  "v1",
+				Resource: "deployments",
+				Name:     "app",
+			},
+		},
+	}
+}
+
+func TestHTTPBindingProvider(t *testing.T) {
+	var calls int32
+	var received ProviderRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		switch r.URL.Path {
+		case "/token":
+			_, _ = w.Write([]byte(`{"token":"s3cr3t"}`))
+		case "/no-store":
+			w.Header().Set("Cache-Control", "no-store")
+			_, _ = w.Write([]byte(`{"token":"s3cr3t"}`))
+		case "/invalid":
+			_, _ = w.Write([]byte(`{"port":5432}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	newProvider := func(allowedURLs ...string) *httpBindingProvider {
+		return NewHTTPBindingProvider(HTTPProviderOptions{
+			AllowedURLs: allowedURLs,
+			CacheTTL:    time.Minute,
+		}).(*httpBindingProvider)
+	}
+
+	t.Run("values are returned", func(t *testing.T) {
+		p := newProvider(srv.URL)
+		values, err := p.Provide(srv.URL+"/token", newProviderRequest())
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"token": "s3cr3t"}, values)
+		require.Equal(t, *newProviderRequest(), received)
+	})
+
+	t.Run("endpoint not allowed", func(t *testing.T) {
+		for _, p := range []*httpBindingProvider{newProvider(), newProvider("https://provider.example.com")} {
+			_, err := p.Provide(srv.URL+"/token", newProviderRequest())
+			require.True(t, IsErrBindingProviderNotAllowed(err), err)
+		}
+	})
+
+	t.Run("endpoint not an HTTP URL", func(t *testing.T) {
+		p := newProvider("file:")
+		_, err := p.Provide("file:///etc/passwd", newProviderRequest())
+		require.Error(t, err)
+		require.False(t, IsErrBindingProviderNotAllowed(err))
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		p := newProvider(srv.URL)
+		_, err := p.Provide(srv.URL+"/fail", newProviderRequest())
+		require.EqualError(t, err, "binding provider "+srv.URL+"/fail returned 500 Internal Server Error")
+	})
+
+	t.Run("invalid response", func(t *testing.T) {
+		p := newProvider(srv.URL)
+		_, err := p.Provide(srv.URL+"/invalid", newProviderRequest())
+		require.Error(t, err)
+	})
+
+	t.Run("responses are cached", func(t *testing.T) {
+		p := newProvider(srv.URL)
+		now := time.Now()
+		p.now = func() time.Time { return now }
+		atomic.StoreInt32(&calls, 0)
+
+		_, err := p.Provide(srv.URL+"/token", newProviderRequest())
+		require.NoError(t, err)
+		values, err := p.Provide(srv.URL+"/token", newProviderRequest())
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"token": "s3cr3t"}, values)
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+		// a different request isn't served from the cache
+		req := newProviderRequest()
+		req.Binding.ServiceID = "other"
+		_, err = p.Provide(srv.URL+"/token", req)
+		require.NoError(t, err)
+		require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+		// neither is an expired response
+		now = now.Add(time.Minute)
+		_, err = p.Provide(srv.URL+"/token", newProviderRequest())
+		require.NoError(t, err)
+		require.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("responses are not cached when the provider says so", func(t *testing.T) {
+		p := newProvider(srv.URL)
+		atomic.StoreInt32(&calls, 0)
+		for i := 0; i < 2; i++ {
+			_, err := p.Provide(srv.URL+"/no-store", newProviderRequest())
+			require.NoError(t, err)
+		}
+		require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+}
+
+func TestHTTPBindingProviderAllowedURLs(t *testing.T) {
+	p := NewHTTPBindingProvider(HTTPProviderOptions{
+		AllowedURLs: []string{"https://provider.example.com/bindings/", "http://tokens.example.com:8080"},
+	}).(*httpBindingProvider)
+
+	for endpoint, allowed := range map[string]bool{
+		"https://provider.example.com/bindings":                 true,
+		"https://provider.example.com/bindings/":                true,
+		"https://provider.example.com/bindings/db":              true,
+		"https://PROVIDER.example.com/bindings/db":              true,
+		"http://tokens.example.com:8080/token":                  true,
+		"http://tokens.example.com:8080":                        true,
+		"https://provider.example.com/bindingsX":                false,
+		"https://provider.example.com/":                         false,
+		"https://provider.example.com/bindings/../admin":        false,
+		"https://provider.example.com/bindings/%2e%2e/admin":    false,
+		"http://provider.example.com/bindings/db":               false,
+		"https://provider.example.com.evil.io/bindings/db":      false,
+		"https://provider.example.com@evil.io/bindings/db":      false,
+		"https://user@provider.example.com/bindings/db":         false,
+		"https://provider.example.com:8443/bindings/db":         false,
+		"http://tokens.example.com/token":                       false,
+		"http://tokens.example.com:80800/token":                 false,
+		"https://evil.io/https://provider.example.com/bindings": false,
+	} {
+		require.Equal(t, allowed, p.isAllowed(endpoint), endpoint)
+	}
+
+	t.Run("unparsable allowed URLs allow nothing", func(t *testing.T) {
+		p := NewHTTPBindingProvider(HTTPProviderOptions{
+			AllowedURLs: []string{"", "provider.example.com", "https://%zz"},
+		}).(*httpBindingProvider)
+		require.False(t, p.isAllowed("https://provider.example.com/bindings"))
+	})
+}
+
+func TestHTTPBindingProviderRedirect(t *testing.T) {
+	var calls int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_, _ = w.Write([]byte(`{"token":"s3cr3t"}`))
+	}))
+	defer target.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL+"/token", http.StatusTemporaryRedirect)
+	}))
+	defer srv.Close()
+
+	p := NewHTTPBindingProvider(HTTPProviderOptions{AllowedURLs: []string{srv.URL}})
+	_, err := p.Provide(srv.URL+"/token", newProviderRequest())
+	require.EqualError(t, err, "binding provider "+srv.URL+"/token returned 307 Temporary Redirect")
+	require.Equal(t, int32(0), atomic.LoadInt32(&calls))
+}
+
+func TestHTTPBindingProviderTLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"token":"s3cr3t"}`))
+	}))
+	defer srv.Close()
+
+	t.Run("untrusted certificate", func(t *testing.T) {
+		p := NewHTTPBindingProvider(HTTPProviderOptions{AllowedURLs: []string{srv.URL}})
+		_, err := p.Provide(srv.URL, newProviderRequest())
+		require.Error(t, err)
+	})
+
+	t.Run("trusted certificate", func(t *testing.T) {
+		pool := x509.NewCertPool()
+		pool.AddCert(srv.Certificate())
+		p := NewHTTPBindingProvider(HTTPProviderOptions{
+			AllowedURLs: []string{srv.URL},
+			TLSConfig:   &tls.Config{RootCAs: pool},
+		})
+		values, err := p.Provide(srv.URL, newProviderRequest())
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"token": "s3cr3t"}, values)
+	})
+}
+
+func TestHTTPBindingProviderTimeout(t *testing.T) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	p := NewHTTPBindingProvider(HTTPProviderOptions{
+		AllowedURLs: []string{srv.URL},
+		Timeout:     50 * time.Millisecond,
+	})
+	_, err := p.Provide(srv.URL, newProviderRequest())
+	require.Error(t, err)
+}
+
+func TestCacheTTL(t *testing.T) {
+	testCases := []struct {
+		cacheControl string
+		expected     time.Duration
+	}{
+		{cacheControl: "", expected: time.Minute},
+		{cacheControl: "private", expected: time.Minute},
+		{cacheControl: "max-age=30", expected: 30 * time.Second},
+		{cacheControl: "private, max-age=0", expected: 0},
+		{cacheControl: "no-store", expected: 0},
+		{cacheControl: "No-Cache", expected: 0},
+		{cacheControl: "max-age=invalid", expected: time.Minute},
+	}
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, cacheTTL(tc.cacheControl, time.Minute), tc.cacheControl)
+	}
+}
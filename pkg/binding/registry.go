@@ -0,0 +1,197 @@
+package binding
+
+import (
+	"fmt"
+	"sync"
+
+	"go.starlark.net/starlark"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// AnyObjectType can be used as DefinitionKey.ObjectType to register a DefinitionFactory handling
+// all object types of a given element type that haven't been explicitly registered.
+const AnyObjectType = "*"
+
+// DefinitionKey identifies the element and object type combination a DefinitionFactory handles.
+type DefinitionKey struct {
+	// ElementType is the value of the "elementType" annotation key, e.g. "string" or "map".
+	ElementType string
+	// ObjectType is the value of the "objectType" annotation key, e.g. "Secret" or "ConfigMap";
+	// AnyObjectType matches every object type.
+	ObjectType string
+}
+
+// DefinitionOptions contains the configuration extracted from a binding annotation, handed to a
+// DefinitionFactory to build a Definition.
+type DefinitionOptions struct {
+	// KubeClient can be used by definitions requiring access to other resources in the cluster.
+	KubeClient dynamic.Interface
+	// OutputName is the name the collected value should be exposed as.
+	OutputName string
+	// Path is the location of the value in the service resource, split in its components.
+	Path []string
+	// Query evaluates Path when it can't be expressed as a sequence of field names, for example when
+	// it holds filters; nil otherwise.
+	Query       *jsonpath.JSONPath
+	ElementType string
+	ObjectType  string
+	SourceKey   string
+	SourceValue string
+	// Script is the source of the script computing the values, for the "script" element type.
+	Script string
+	// program is Script already compiled by the annotation parser, kept internal so that Starlark
+	// isn't part of the API of the package; Script is compiled by the definition when nil.
+	program *starlark.Program
+	// BindAs is the medium the collected value should be delivered through, or empty for the
+	// Service Binding default.
+	BindAs BindingType
+}
+
+// DefinitionFactory builds a Definition from the options extracted from a binding annotation.
+type DefinitionFactory func(opts DefinitionOptions) (Definition, error)
+
+// ErrUnsupportedDefinition is returned when no DefinitionFactory has been registered for the
+// element and object type combination informed in a binding annotation.
+type ErrUnsupportedDefinition DefinitionKey
+
+func (e ErrUnsupportedDefinition) Error() string {
+	return fmt.Sprintf("no definition registered for elementType %q and objectType %q",
+		e.ElementType, e.ObjectType)
+}
+
+func IsErrUnsupportedDefinition(err error) bool {
+	_, ok := err.(ErrUnsupportedDefinition)
+	return ok
+}
+
+// DefinitionRegistry holds the DefinitionFactory instances used to build definitions from binding
+// annotations; it is safe for concurrent use.
+type DefinitionRegistry struct {
+	mu        sync.RWMutex
+	factories map[DefinitionKey]DefinitionFactory
+}
+
+// NewDefinitionRegistry returns an empty registry.
+func NewDefinitionRegistry() *DefinitionRegistry {
+	return &DefinitionRegistry{factories: make(map[DefinitionKey]DefinitionFactory)}
+}
+
+// NewBuiltinDefinitionRegistry returns a registry containing the definitions supported by the
+// operator out of the box.
+func NewBuiltinDefinitionRegistry() *DefinitionRegistry {
+	r := NewDefinitionRegistry()
+	r.Register(DefinitionKey{string(stringElementType), string(stringObjectType)}, newStringDefinition)
+	r.Register(DefinitionKey{string(stringElementType), string(secretObjectType)}, newStringFromDataFieldDefinition)
+	r.Register(DefinitionKey{string(stringElementType), string(configMapObjectType)}, newStringFromDataFieldDefinition)
+	r.Register(DefinitionKey{string(mapElementType), string(secretObjectType)}, newMapFromDataFieldDefinition)
+	r.Register(DefinitionKey{string(mapElementType), string(configMapObjectType)}, newMapFromDataFieldDefinition)
+	r.Register(DefinitionKey{string(mapElementType), string(stringObjectType)}, newStringOfMapDefinition)
+	r.Register(DefinitionKey{string(sliceOfMapsElementType), AnyObjectType}, newSliceOfMapsFromPathDefinition)
+	r.Register(DefinitionKey{string(sliceOfStringsElementType), AnyObjectType}, newSliceOfStringsFromPathDefinition)
+	r.Register(DefinitionKey{string(scriptElementType), AnyObjectType}, newScriptDefinition)
+	return r
+}
+
+// DefaultDefinitionRegistry is the registry used when none has been informed; factories registered
+// through RegisterDefinition end up here.
+var DefaultDefinitionRegistry = NewBuiltinDefinitionRegistry()
+
+// RegisterDefinition registers the given factory in DefaultDefinitionRegistry.
+func RegisterDefinition(key DefinitionKey, factory DefinitionFactory) {
+	DefaultDefinitionRegistry.Register(key, factory)
+}
+
+// Register associates the factory with the given key, replacing a factory previously registered
+// with the same key.
+func (r *DefinitionRegistry) Register(key DefinitionKey, factory DefinitionFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factories[key] = factory
+}
+
+// Lookup returns the factory registered for the given key, falling back to the factory registered
+// for the key's element type and AnyObjectType.
+func (r *DefinitionRegistry) Lookup(key DefinitionKey) (DefinitionFactory, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if f, ok := r.factories[key]; ok {
+		return f, true
+	}
+	f, ok := r.factories[DefinitionKey{ElementType: key.ElementType, ObjectType: AnyObjectType}]
+	return f, ok
+}
+
+// Build builds a Definition using the factory registered for the element and object type present
+// in opts; ErrUnsupportedDefinition is returned if no factory can be found.
+func (r *DefinitionRegistry) Build(opts DefinitionOptions) (Definition, error) {
+	key := DefinitionKey{ElementType: opts.ElementType, ObjectType: opts.ObjectType}
+	factory, ok := r.Lookup(key)
+	if !ok {
+		return nil, ErrUnsupportedDefinition(key)
+	}
+	return factory(opts)
+}
+
+func newStringDefinition(opts DefinitionOptions) (Definition, error) {
+	return &stringDefinition{
+		outputName: opts.OutputName,
+		path:       opts.Path,
+		query:      opts.Query,
+		bindAs:     opts.BindAs,
+	}, nil
+}
+
+func newStringFromDataFieldDefinition(opts DefinitionOptions) (Definition, error) {
+	return &stringFromDataFieldDefinition{
+		kubeClient: opts.KubeClient,
+		objectType: objectType(opts.ObjectType),
+		outputName: opts.OutputName,
+		path:       opts.Path,
+		query:      opts.Query,
+		sourceKey:  opts.SourceKey,
+		bindAs:     opts.BindAs,
+	}, nil
+}
+
+func newMapFromDataFieldDefinition(opts DefinitionOptions) (Definition, error) {
+	return &mapFromDataFieldDefinition{
+		kubeClient:  opts.KubeClient,
+		objectType:  objectType(opts.ObjectType),
+		outputName:  opts.OutputName,
+		path:        opts.Path,
+		query:       opts.Query,
+		sourceValue: opts.SourceValue,
+		bindAs:      opts.BindAs,
+	}, nil
+}
+
+func newStringOfMapDefinition(opts DefinitionOptions) (Definition, error) {
+	return &stringOfMapDefinition{
+		outputName: opts.OutputName,
+		path:       opts.Path,
+		query:      opts.Query,
+		bindAs:     opts.BindAs,
+	}, nil
+}
+
+func newSliceOfMapsFromPathDefinition(opts DefinitionOptions) (Definition, error) {
+	return &sliceOfMapsFromPathDefinition{
+		outputName:  opts.OutputName,
+		path:        opts.Path,
+		query:       opts.Query,
+		sourceKey:   opts.SourceKey,
+		sourceValue: opts.SourceValue,
+		bindAs:      opts.BindAs,
+	}, nil
+}
+
+func newSliceOfStringsFromPathDefinition(opts DefinitionOptions) (Definition, error) {
+	return &sliceOfStringsFromPathDefinition{
+		outputName:  opts.OutputName,
+		path:        opts.Path,
+		query:       opts.Query,
+		sourceValue: opts.SourceValue,
+		bindAs:      opts.BindAs,
+	}, nil
+}
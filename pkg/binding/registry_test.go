@@ -0,0 +1,96 @@
+package binding
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// conditionDefinition is an example of an in-house extractor, collecting the status of a condition
+// found in the service's status.conditions.
+type conditionDefinition struct {
+	outputName string
+	path       []string
+}
+
+func (d *conditionDefinition) GetPath() []string { return d.path[0 : len(d.path)-1] }
+
+func (d *conditionDefinition) GetBindAs() BindingType { return "" }
+
+func (d *conditionDefinition) Apply(u *unstructured.Unstructured) (Value, error) {
+	conditions, _, err := unstructured.NestedSlice(u.Object, d.path...)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range conditions {
+		if m, ok := c.(map[string]interface{}); ok && m["type"] == d.outputName {
+			return &value{v: map[string]interface{}{d.outputName: m["status"]}}, nil
+		}
+	}
+	return nil, ErrValueNotFound
+}
+
+func TestDefinitionRegistry(t *testing.T) {
+	conditionKey := DefinitionKey{ElementType: "string", ObjectType: "Condition"}
+	newConditionDefinition := func(opts DefinitionOptions) (Definition, error) {
+		return &conditionDefinition{outputName: opts.OutputName, path: opts.Path}, nil
+	}
+
+	t.Run("builtin registry does not support unknown combinations", func(t *testing.T) {
+		r := NewBuiltinDefinitionRegistry()
+		_, err := r.Build(DefinitionOptions{ElementType: conditionKey.ElementType, ObjectType: conditionKey.ObjectType})
+		require.Error(t, err)
+		require.True(t, IsErrUnsupportedDefinition(err))
+	})
+
+	t.Run("any object type is used as fallback", func(t *testing.T) {
+		r := NewBuiltinDefinitionRegistry()
+		d, err := r.Build(DefinitionOptions{
+			ElementType: "sliceOfStrings",
+			ObjectType:  "Secret",
+			OutputName:  "tags",
+			Path:        []string{"status", "tags"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, &sliceOfStringsFromPathDefinition{
+			outputName: "tags",
+			path:       []string{"status", "tags"},
+		}, d)
+	})
+
+	t.Run("registered factory is used by the annotation builder", func(t *testing.T) {
+		r := NewBuiltinDefinitionRegistry()
+		r.Register(conditionKey, newConditionDefinition)
+
+		builder := &annotationBackedDefinitionBuilder{
+			name:     "service.binding/Ready",
+			value:    "path={.status.conditions},objectType=Condition",
+			registry: r,
+		}
+		d, err := builder.Build()
+		require.NoError(t, err)
+
+		u := &unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Ready", "status": "True"},
+				},
+			},
+		}}
+		v, err := d.Apply(u)
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"Ready": "True"}, v.Get())
+	})
+
+	t.Run("registered factory replaces previous one", func(t *testing.T) {
+		r := NewDefinitionRegistry()
+		r.Register(conditionKey, func(opts DefinitionOptions) (Definition, error) {
+			return nil, errors.New("replaced")
+		})
+		r.Register(conditionKey, newConditionDefinition)
+		_, err := r.Build(DefinitionOptions{ElementType: conditionKey.ElementType, ObjectType: conditionKey.ObjectType})
+		require.NoError(t, err)
+	})
+}
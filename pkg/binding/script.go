@@ -0,0 +1,268 @@
+package binding
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.starlark.net/starlark"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// ScriptAnnotationPrefix is the prefix of annotations carrying a Starlark script computing
+	// binding values, in the form "service.binding.script/<name>"; the script defines a
+	// bind(service) function returning a dict of binding values.
+	ScriptAnnotationPrefix = "service.binding.script"
+	// scriptFunction is the function scripts are required to define.
+	scriptFunction = "bind"
+	// scriptMaxSteps is the maximum number of computation steps a script can execute.
+	scriptMaxSteps = 1000000
+	// scriptTimeout is the time limit of a script execution.
+	scriptTimeout = time.Second
+	// scriptMaxLookups is the maximum number of objects a script can read from the cluster.
+	scriptMaxLookups = 10
+)
+
+// scriptElementType indicates the values are computed by a script.
+const scriptElementType elementType = "script"
+
+var configMapsGVR = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+// scriptPredeclared are the names available to scripts besides the Starlark builtins.
+var scriptPredeclared = map[string]bool{"secret": true, "config_map": true}
+
+// compileScript parses and resolves the given script.
+func compileScript(name string, src string) (*starlark.Program, error) {
+	_, prog, err := starlark.SourceProgram(name, src, func(n string) bool { return scriptPredeclared[n] })
+	return prog, err
+}
+
+// scriptDefinition collects the values returned by the bind function of a Starlark script, given
+// the service resource. Scripts can read Secrets and ConfigMaps in the namespace of the service
+// through the secret(name) and config_map(name) functions, returning their (decoded) data or None
+// when not found; no other access to the outside world is available, and executions are limited
+// in steps and time.
+type scriptDefinition struct {
+	kubeClient dynamic.Interface
+	name       string
+	program    *starlark.Program
+	bindAs     BindingType
+}
+
+var _ Definition = (*scriptDefinition)(nil)
+
+func newScriptDefinition(opts DefinitionOptions) (Definition, error) {
+	program := opts.program
+	if program == nil {
+		var err error
+		if program, err = compileScript(opts.OutputName, opts.Script); err != nil {
+			return nil, err
+		}
+	}
+	return &scriptDefinition{
+		kubeClient: opts.KubeClient,
+		name:       opts.OutputName,
+		program:    program,
+		bindAs:     opts.BindAs,
+	}, nil
+}
+
+// GetPath returns no path, since script values don't come from a single location of the service.
+func (d *scriptDefinition) GetPath() []string { return nil }
+
+func (d *scriptDefinition) GetBindAs() BindingType { return d.bindAs }
+
+func (d *scriptDefinition) Apply(u *unstructured.Unstructured) (Value, error) {
+	thread := &starlark.Thread{
+		Name:  ScriptAnnotationPrefix + "/" + d.name,
+		Print: func(*starlark.Thread, string) {},
+	}
+	thread.SetMaxExecutionSteps(scriptMaxSteps)
+	timer := time.AfterFunc(scriptTimeout, func() { thread.Cancel("timeout") })
+	defer timer.Stop()
+
+	lookups := 0
+	lookup := func(name string, gvr schema.GroupVersionResource, decode bool) *starlark.Builtin {
+		return starlark.NewBuiltin(name, func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			var objName string
+			if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 1, &objName); err != nil {
+				return nil, err
+			}
+			if lookups++; lookups > scriptMaxLookups {
+				return nil, fmt.Errorf("%s: too many lookups, at most %d are allowed", fn.Name(), scriptMaxLookups)
+			}
+			return d.lookupData(gvr, u.GetNamespace(), objName, decode)
+		})
+	}
+
+	service, err := toStarlark(u.Object)
+	if err != nil {
+		return nil, err
+	}
+	globals, err := d.program.Init(thread, starlark.StringDict{
+		"secret":     lookup("secret", secretsGVR, true),
+		"config_map": lookup("config_map", configMapsGVR, false),
+	})
+	if err != nil {
+		return nil, err
+	}
+	bind, ok := globals[scriptFunction].(starlark.Callable)
+	if !ok {
+		return nil, fmt.Errorf("script %q doesn't define a %s function", d.name, scriptFunction)
+	}
+	ret, err := starlark.Call(thread, bind, starlark.Tuple{service}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]interface{})
+	if ret == starlark.None {
+		return &value{v: out}, nil
+	}
+	dict, ok := ret.(*starlark.Dict)
+	if !ok {
+		return nil, fmt.Errorf("script %q returned %s instead of a dict", d.name, ret.Type())
+	}
+	for _, item := range dict.Items() {
+		k, ok := starlark.AsString(item[0])
+		if !ok {
+			return nil, fmt.Errorf("script %q returned a dict with non string key %s", d.name, item[0])
+		}
+		v, err := fromStarlark(item[1])
+		if err != nil {
+			return nil, fmt.Errorf("script %q returned an invalid value for %q: %v", d.name, k, err)
+		}
+		if v != nil {
+			out[k] = v
+		}
+	}
+	return &value{v: out}, nil
+}
+
+// lookupData returns the data of the given object, or None if it doesn't exist.
+func (d *scriptDefinition) lookupData(gvr schema.GroupVersionResource, ns string, name string, decode bool) (starlark.Value, error) {
+	if d.kubeClient == nil {
+		return nil, errors.New("kubeClient required for this functionality")
+	}
+	obj, err := d.kubeClient.Resource(gvr).Namespace(ns).Get(context.TODO(), name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return starlark.None, nil
+	} else if err != nil {
+		return nil, err
+	}
+	data, _, err := unstructured.NestedStringMap(obj.Object, "data")
+	if err != nil {
+		return nil, err
+	}
+	dict := starlark.NewDict(len(data))
+	for k, v := range data {
+		if decode {
+			n, err := base64.StdEncoding.DecodeString(v)
+			if err != nil {
+				return nil, err
+			}
+			v = string(n)
+		}
+		if err := dict.SetKey(starlark.String(k), starlark.String(v)); err != nil {
+			return nil, err
+		}
+	}
+	dict.Freeze()
+	return dict, nil
+}
+
+// toStarlark converts the given unstructured content to a frozen Starlark value.
+func toStarlark(v interface{}) (starlark.Value, error) {
+	switch t := v.(type) {
+	case nil:
+		return starlark.None, nil
+	case string:
+		return starlark.String(t), nil
+	case bool:
+		return starlark.Bool(t), nil
+	case int64:
+		return starlark.MakeInt64(t), nil
+	case int:
+		return starlark.MakeInt(t), nil
+	case float64:
+		return starlark.Float(t), nil
+	case []interface{}:
+		elems := make([]starlark.Value, 0, len(t))
+		for _, e := range t {
+			sv, err := toStarlark(e)
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, sv)
+		}
+		l := starlark.NewList(elems)
+		l.Freeze()
+		return l, nil
+	case map[string]interface{}:
+		dict := starlark.NewDict(len(t))
+		for k, e := range t {
+			sv, err := toStarlark(e)
+			if err != nil {
+				return nil, err
+			}
+			if err = dict.SetKey(starlark.String(k), sv); err != nil {
+				return nil, err
+			}
+		}
+		dict.Freeze()
+		return dict, nil
+	}
+	return nil, fmt.Errorf("unsupported type %T", v)
+}
+
+// fromStarlark converts the given Starlark value to a binding value: scalars become strings, lists
+// and dicts become slices and maps; None is returned as nil.
+func fromStarlark(v starlark.Value) (interface{}, error) {
+	switch t := v.(type) {
+	case starlark.NoneType:
+		return nil, nil
+	case starlark.String:
+		return string(t), nil
+	case starlark.Bool:
+		return strconv.FormatBool(bool(t)), nil
+	case starlark.Int:
+		return t.String(), nil
+	case starlark.Float:
+		return strconv.FormatFloat(float64(t), 'g', -1, 64), nil
+	case *starlark.List:
+		out := make([]interface{}, 0, t.Len())
+		for i := 0; i < t.Len(); i++ {
+			e, err := fromStarlark(t.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, e)
+		}
+		return out, nil
+	case starlark.Tuple:
+		return fromStarlark(starlark.NewList(t))
+	case *starlark.Dict:
+		out := make(map[string]interface{}, t.Len())
+		for _, item := range t.Items() {
+			k, ok := starlark.AsString(item[0])
+			if !ok {
+				return nil, fmt.Errorf("non string key %s", item[0])
+			}
+			e, err := fromStarlark(item[1])
+			if err != nil {
+				return nil, err
+			}
+			out[k] = e
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", v.Type())
+}
@@ -0,0 +1,188 @@
+package binding
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/redhat-developer/service-binding-operator/test/mocks"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestScriptDefinition(t *testing.T) {
+	f := mocks.NewFake(t, "test-namespace")
+	f.AddMockedUnstructuredSecret("dbCredentials-secret")
+
+	u := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"namespace": "test-namespace",
+				"name":      "kafka",
+			},
+			"spec": map[string]interface{}{
+				"port": int64(9092),
+			},
+			"status": map[string]interface{}{
+				"dbCredentials": "dbCredentials-secret",
+				"listeners": []interface{}{
+					map[string]interface{}{"type": "plain", "bootstrapServers": "kafka:9092"},
+					map[string]interface{}{"type": "tls", "bootstrapServers": "kafka:9093"},
+				},
+				"replicas": []interface{}{
+					map[string]interface{}{"host": "kafka-0", "ready": false},
+					map[string]interface{}{"host": "kafka-1", "ready": true},
+					map[string]interface{}{"host": "kafka-2", "ready": true},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		description   string
+		script        string
+		expectedValue interface{}
+		expectedError string
+	}{
+		{
+			description: "loop over listeners",
+			script: `
+def bind(service):
+    for l in service["status"]["listeners"]:
+        if l["type"] == "tls":
+            return {"bootstrapServers": l["bootstrapServers"]}
+`,
+			expectedValue: map[string]interface{}{"bootstrapServers": "kafka:9093"},
+		},
+		{
+			description: "first ready replica and composite values",
+			script: `
+def bind(service):
+    ready = [r["host"] for r in service["status"]["replicas"] if r["ready"]]
+    return {
+        "host": ready[0],
+        "hosts": ready,
+        "port": service["spec"]["port"],
+        "url": "kafka://%s:%d" % (ready[0], service["spec"]["port"]),
+        "tls": True,
+    }
+`,
+			expectedValue: map[string]interface{}{
+				"host":  "kafka-1",
+				"hosts": []interface{}{"kafka-1", "kafka-2"},
+				"port":  "9092",
+				"url":   "kafka://kafka-1:9092",
+				"tls":   "true",
+			},
+		},
+		{
+			description: "referenced secret",
+			script: `
+def bind(service):
+    creds = secret(service["status"]["dbCredentials"])
+    return {"user": creds["username"], "missing": secret("missing")}
+`,
+			expectedValue: map[string]interface{}{"user": "user"},
+		},
+		{
+			description:   "nothing returned",
+			script:        "def bind(service):\n    pass\n",
+			expectedValue: map[string]interface{}{},
+		},
+		{
+			description:   "missing bind function",
+			script:        "x = 1\n",
+			expectedError: `script "test" doesn't define a bind function`,
+		},
+		{
+			description:   "non dict returned",
+			script:        "def bind(service):\n    return 1\n",
+			expectedError: `script "test" returned int instead of a dict`,
+		},
+		{
+			description:   "unsupported value returned",
+			script:        "def bind(service):\n    return {\"f\": bind}\n",
+			expectedError: `script "test" returned an invalid value for "f": unsupported type function`,
+		},
+		{
+			description:   "service can't be modified",
+			script:        "def bind(service):\n    service[\"spec\"][\"port\"] = 1\n",
+			expectedError: "cannot insert into frozen hash table",
+		},
+		{
+			description:   "too many steps",
+			script:        "def bind(service):\n    for i in range(100000000):\n        pass\n",
+			expectedError: "too many steps",
+		},
+		{
+			description:   "too many lookups",
+			script:        "def bind(service):\n    for i in range(20):\n        secret(\"missing\")\n",
+			expectedError: "too many lookups",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			d, err := newScriptDefinition(DefinitionOptions{
+				KubeClient: f.FakeDynClient(),
+				OutputName: "test",
+				Script:     tc.script,
+			})
+			require.NoError(t, err)
+			val, err := d.Apply(u)
+			if len(tc.expectedError) > 0 {
+				require.Error(t, err)
+				require.True(t, strings.Contains(err.Error(), tc.expectedError), err.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedValue, val.Get())
+		})
+	}
+}
+
+func TestScriptAnnotation(t *testing.T) {
+	script := `
+def bind(service):
+    return {"endpoint": service["status"]["host"] + ":" + str(service["status"]["port"])}
+`
+	u := unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"status": map[string]interface{}{
+				"host": "db",
+				"port": int64(5432),
+			},
+		},
+	}
+
+	t.Run("values are collected", func(t *testing.T) {
+		require.True(t, IsBindingAnnotation("service.binding.script/endpoint"))
+		h, err := NewSpecHandler(nil, "service.binding.script/endpoint", script, u, nil, nil)
+		require.NoError(t, err)
+		r, err := h.Handle()
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"endpoint": "db:5432"}, r.Data)
+		require.Empty(t, r.RawData)
+	})
+
+	t.Run("description", func(t *testing.T) {
+		d, err := Describe("service.binding.script/endpoint", script)
+		require.NoError(t, err)
+		require.Equal(t, &Description{
+			Name:        "endpoint",
+			ElementType: "script",
+			ObjectType:  "string",
+		}, d)
+	})
+
+	t.Run("name is required", func(t *testing.T) {
+		_, err := Describe("service.binding.script", script)
+		require.Error(t, err)
+	})
+
+	t.Run("invalid script", func(t *testing.T) {
+		_, err := Describe("service.binding.script/endpoint", "def bind(service)\n")
+		require.Error(t, err)
+		_, err = Describe("service.binding.script/endpoint", "def bind(service):\n    return load_file()\n")
+		require.Error(t, err)
+	})
+}
@@ -1,11 +1,14 @@
 package binding
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/mitchellh/copystructure"
+	"github.com/redhat-developer/service-binding-operator/pkg/envvars"
 	"github.com/redhat-developer/service-binding-operator/pkg/nested"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/dynamic"
@@ -19,6 +22,11 @@ const (
 	TypeVolumeMount BindingType = "volumemount"
 	// TypeEnvVar indicates the binding should happen through environment variables.
 	TypeEnvVar BindingType = "env"
+
+	// bindingTypeVolumeAlias is accepted as an alternative spelling of TypeVolumeMount.
+	bindingTypeVolumeAlias BindingType = "volume"
+	// bindingTypeEnvVarAlias is accepted as an alternative spelling of TypeEnvVar.
+	bindingTypeEnvVarAlias BindingType = "envVar"
 )
 
 // result contains data that has been collected by an annotation handler.
@@ -26,9 +34,12 @@ type result struct {
 	// Data contains the annotation data collected by an annotation handler inside a deep structure
 	// with its root being the value specified in the Path field.
 	Data map[string]interface{}
-	// Type indicates where the Object field should be injected in the application; can be either
-	// "env" or "volumemount".
+	// Type indicates where the Data field should be injected in the application; can be either
+	// "env", "volumemount" or empty, in which case the Service Binding's default applies.
 	Type BindingType
+	// Arrays configures how the slices found in the Data field should be rendered; an empty format
+	// means the Service Binding's default applies.
+	Arrays envvars.ArrayRendering
 	// Path is the nested location the collected data can be found in the Data field.
 	Path string
 	// RawData contains the annotation data collected by an annotation handler
@@ -49,12 +60,30 @@ func IsErrHandlerNotFound(err error) bool {
 	return ok
 }
 
+// ErrRequiredValueNotFound is returned when a value declared by a binding annotation, neither
+// optional nor with a default value, can't be found.
+type ErrRequiredValueNotFound struct {
+	// Annotation is the name of the annotation declaring the value.
+	Annotation string
+	// Path is the location of the value in the service resource.
+	Path string
+}
+
+func (e ErrRequiredValueNotFound) Error() string {
+	return fmt.Sprintf("required value not found at path %q declared by annotation %q", e.Path, e.Annotation)
+}
+
+func IsErrRequiredValueNotFound(err error) bool {
+	return errors.As(err, &ErrRequiredValueNotFound{})
+}
+
 type SpecHandler struct {
 	kubeClient      dynamic.Interface
 	obj             unstructured.Unstructured
 	annotationKey   string
 	annotationValue string
 	restMapper      meta.RESTMapper
+	registry        *DefinitionRegistry
 }
 
 func (s *SpecHandler) Handle() (result, error) {
@@ -62,13 +91,31 @@ func (s *SpecHandler) Handle() (result, error) {
 		kubeClient: s.kubeClient,
 		name:       s.annotationKey,
 		value:      s.annotationValue,
+		registry:   s.registry,
 	}
-	d, err := builder.Build()
+	outputName, mod, err := builder.parse()
+	if err != nil {
+		return result{}, err
+	}
+	d, err := builder.build(outputName, mod)
 	if err != nil {
 		return result{}, err
 	}
 
 	val, err := d.Apply(&s.obj)
+	if errors.Is(err, ErrValueNotFound) || k8serrors.IsNotFound(err) {
+		switch {
+		case mod.defaultValue != nil:
+			val, err = &value{v: map[string]interface{}{outputName: *mod.defaultValue}}, nil
+		case mod.optional:
+			val, err = &value{v: map[string]interface{}{}}, nil
+		default:
+			err = ErrRequiredValueNotFound{
+				Annotation: s.annotationKey,
+				Path:       strings.Join(mod.path, "."),
+			}
+		}
+	}
 	if err != nil {
 		return result{}, err
 	}
@@ -95,11 +142,18 @@ func (s *SpecHandler) Handle() (result, error) {
 		return result{}, err
 	}
 
-	rawData := nested.ComposeValue(cpy, nested.NewPath(path))
+	// values not coming from a location of the service, such as the ones computed by scripts, aren't
+	// reflected in it
+	var rawData map[string]interface{}
+	if len(path) > 0 {
+		rawData = nested.ComposeValue(cpy, nested.NewPath(path))
+	}
 
 	return result{
 		Data:    out,
 		RawData: rawData,
+		Type:    d.GetBindAs(),
+		Arrays:  mod.arrays,
 	}, nil
 }
 
@@ -109,12 +163,17 @@ func NewSpecHandler(
 	annotationValue string,
 	obj unstructured.Unstructured,
 	restMapper meta.RESTMapper,
+	registry *DefinitionRegistry,
 ) (*SpecHandler, error) {
+	if registry == nil {
+		registry = DefaultDefinitionRegistry
+	}
 	return &SpecHandler{
 		kubeClient:      kubeClient,
 		obj:             obj,
 		annotationKey:   annotationKey,
 		annotationValue: annotationValue,
 		restMapper:      restMapper,
+		registry:        registry,
 	}, nil
 }
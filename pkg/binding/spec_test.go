@@ -3,6 +3,7 @@ package binding
 import (
 	"testing"
 
+	"github.com/redhat-developer/service-binding-operator/pkg/envvars"
 	"github.com/redhat-developer/service-binding-operator/pkg/testutils"
 	"github.com/redhat-developer/service-binding-operator/test/mocks"
 	"github.com/stretchr/testify/require"
@@ -20,6 +21,8 @@ func TestSpecHandler(t *testing.T) {
 		resources       []runtime.Object
 		expectedData    interface{}
 		expectedRawData map[string]interface{}
+		expectedType    BindingType
+		expectedArrays  envvars.ArrayRendering
 	}
 
 	assertHandler := func(args args) func(*testing.T) {
@@ -38,6 +41,7 @@ func TestSpecHandler(t *testing.T) {
 				args.value,
 				unstructured.Unstructured{Object: args.service},
 				restMapper,
+				nil,
 			)
 			require.NoError(t, err)
 			got, err := handler.Handle()
@@ -45,6 +49,8 @@ func TestSpecHandler(t *testing.T) {
 			require.NotNil(t, got)
 			require.Equal(t, args.expectedData, got.Data, "Data does not match expected")
 			require.Equal(t, args.expectedRawData, got.RawData, "RawData does not match expected")
+			require.Equal(t, args.expectedType, got.Type, "Type does not match expected")
+			require.Equal(t, args.expectedArrays, got.Arrays, "Arrays does not match expected")
 		}
 	}
 
@@ -385,4 +391,137 @@ func TestSpecHandler(t *testing.T) {
 			},
 		},
 	}))
+
+	t.Run("should return the binding type declared in the annotation", assertHandler(args{
+		name:  "service.binding/ca.crt",
+		value: "path={.status.tls},objectType=Secret,sourceValue=ca.crt,bindAs=volumemount",
+		service: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"namespace": "the-namespace",
+			},
+			"status": map[string]interface{}{
+				"tls": "the-secret-resource-name",
+			},
+		},
+		resources: []runtime.Object{
+			&corev1.Secret{
+				TypeMeta: metav1.TypeMeta{
+					Kind: "Secret",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "the-namespace",
+					Name:      "the-secret-resource-name",
+				},
+
+				Data: map[string][]byte{
+					"ca.crt": []byte("-----BEGIN CERTIFICATE-----"),
+				},
+			},
+		},
+		expectedData: map[string]interface{}{
+			"ca.crt": "-----BEGIN CERTIFICATE-----",
+		},
+		expectedRawData: map[string]interface{}{
+			"status": map[string]interface{}{
+				"tls": map[string]interface{}{
+					"ca.crt": "-----BEGIN CERTIFICATE-----",
+				},
+			},
+		},
+		expectedType: TypeVolumeMount,
+	}))
+
+	t.Run("should return the array rendering declared in the annotation", assertHandler(args{
+		name:  "service.binding.json/bootstrapServers",
+		value: `{"path": "{.status.bootstrapServers}", "elementType": "sliceOfStrings", "arrayFormat": "join", "arraySeparator": ";"}`,
+		service: map[string]interface{}{
+			"status": map[string]interface{}{
+				"bootstrapServers": []interface{}{"kafka-0:9092", "kafka-1:9092"},
+			},
+		},
+		expectedData: map[string]interface{}{
+			"bootstrapServers": []interface{}{"kafka-0:9092", "kafka-1:9092"},
+		},
+		expectedRawData: map[string]interface{}{
+			"status": map[string]interface{}{
+				"bootstrapServers": []interface{}{"kafka-0:9092", "kafka-1:9092"},
+			},
+		},
+		expectedArrays: envvars.ArrayRendering{Format: envvars.ArrayFormatJoin, Separator: ";"},
+	}))
+}
+
+func TestSpecHandlerMissingValues(t *testing.T) {
+	service := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"namespace": "the-namespace",
+		},
+		"status": map[string]interface{}{
+			"dbCredentials": "the-secret-resource-name",
+		},
+	}
+
+	handle := func(t *testing.T, name, value string) (result, error) {
+		f := mocks.NewFake(t, "test")
+		handler, err := NewSpecHandler(
+			f.FakeDynClient(),
+			name,
+			value,
+			unstructured.Unstructured{Object: service},
+			testutils.BuildTestRESTMapper(),
+			nil,
+		)
+		require.NoError(t, err)
+		return handler.Handle()
+	}
+
+	t.Run("required value", func(t *testing.T) {
+		_, err := handle(t, "service.binding/password", "path={.status.password}")
+		require.Error(t, err)
+		require.True(t, IsErrRequiredValueNotFound(err))
+		require.Equal(t, ErrRequiredValueNotFound{
+			Annotation: "service.binding/password",
+			Path:       "status.password",
+		}, err)
+	})
+
+	t.Run("required value from missing secret", func(t *testing.T) {
+		_, err := handle(t, "service.binding/password",
+			"path={.status.dbCredentials},objectType=Secret,sourceKey=password")
+		require.True(t, IsErrRequiredValueNotFound(err))
+	})
+
+	t.Run("optional value", func(t *testing.T) {
+		got, err := handle(t, "service.binding/password", "path={.status.password},optional=true")
+		require.NoError(t, err)
+		require.Empty(t, got.Data)
+	})
+
+	t.Run("default value", func(t *testing.T) {
+		got, err := handle(t, "service.binding/port", "path={.status.port},default=5432")
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"port": "5432"}, got.Data)
+		require.Equal(t, map[string]interface{}{
+			"status": map[string]interface{}{"port": "5432"},
+		}, got.RawData)
+	})
+
+	t.Run("empty default value", func(t *testing.T) {
+		got, err := handle(t, "service.binding/options", "path={.status.options},default=")
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"options": ""}, got.Data)
+	})
+
+	t.Run("default value containing separators declared as JSON", func(t *testing.T) {
+		got, err := handle(t, "service.binding.json/options",
+			`{"path": "{.status.options}", "default": "sslmode=require,connect_timeout=10"}`)
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"options": "sslmode=require,connect_timeout=10"}, got.Data)
+	})
+
+	t.Run("optional value declared as JSON", func(t *testing.T) {
+		got, err := handle(t, "service.binding.json/password", `{"path": "{.status.password}", "optional": true}`)
+		require.NoError(t, err)
+		require.Empty(t, got.Data)
+	})
 }
@@ -0,0 +1,139 @@
+package envvars
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ArrayFormat determines how the slices found in a value are rendered into environment variables.
+type ArrayFormat string
+
+const (
+	// ArrayFormatIndexed renders each element of a slice as its own variable, named after its
+	// index; e.g. HOSTS_0 and HOSTS_1.
+	ArrayFormatIndexed ArrayFormat = "Indexed"
+	// ArrayFormatJoin renders a slice as a single variable, joining its elements with a separator;
+	// e.g. HOSTS=a,b.
+	ArrayFormatJoin ArrayFormat = "Join"
+	// ArrayFormatJSON renders a slice as a single variable holding its JSON encoding; e.g.
+	// HOSTS=["a","b"].
+	ArrayFormatJSON ArrayFormat = "JSON"
+
+	// DefaultArraySeparator joins the elements of slices rendered with ArrayFormatJoin when no
+	// separator has been informed.
+	DefaultArraySeparator = ","
+)
+
+// ParseArrayFormat returns the ArrayFormat named s, ignoring case; an empty name stands for
+// ArrayFormatIndexed.
+func ParseArrayFormat(s string) (ArrayFormat, error) {
+	for _, f := range []ArrayFormat{ArrayFormatIndexed, ArrayFormatJoin, ArrayFormatJSON} {
+		if strings.EqualFold(s, string(f)) {
+			return f, nil
+		}
+	}
+	if len(s) == 0 {
+		return ArrayFormatIndexed, nil
+	}
+	return "", fmt.Errorf("unknown array format %q", s)
+}
+
+// ArrayRendering configures how the slices found in a value are rendered.
+type ArrayRendering struct {
+	// Format is the format of the slices; empty stands for ArrayFormatIndexed.
+	Format ArrayFormat
+	// Separator joins the elements of slices rendered with ArrayFormatJoin; empty stands for
+	// DefaultArraySeparator.
+	Separator string
+}
+
+// RenderArrays returns a copy of obj where slices, at any depth, are replaced by the string they
+// are rendered to, ready to be handed to Build; obj is returned as it is with ArrayFormatIndexed,
+// leaving the slices to Build. Joined elements that are neither strings, numbers nor booleans are
+// rendered as JSON.
+func RenderArrays(obj interface{}, r ArrayRendering) (interface{}, error) {
+	if len(r.Format) == 0 || r.Format == ArrayFormatIndexed {
+		return obj, nil
+	}
+	switch val := obj.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, v := range val {
+			rendered, err := RenderArrays(v, r)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = rendered
+		}
+		return m, nil
+	case []map[string]interface{}, []interface{}, []string:
+		return r.render(val)
+	default:
+		return obj, nil
+	}
+}
+
+// render returns the string the given slice is rendered to.
+func (r ArrayRendering) render(slice interface{}) (string, error) {
+	switch r.Format {
+	case ArrayFormatJSON:
+		b, err := json.Marshal(slice)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	case ArrayFormatJoin:
+		var elements []string
+		switch val := slice.(type) {
+		case []string:
+			elements = val
+		case []interface{}:
+			for _, e := range val {
+				s, err := renderElement(e)
+				if err != nil {
+					return "", err
+				}
+				elements = append(elements, s)
+			}
+		case []map[string]interface{}:
+			for _, e := range val {
+				s, err := renderElement(e)
+				if err != nil {
+					return "", err
+				}
+				elements = append(elements, s)
+			}
+		}
+		sep := r.Separator
+		if len(sep) == 0 {
+			sep = DefaultArraySeparator
+		}
+		return strings.Join(elements, sep), nil
+	default:
+		return "", fmt.Errorf("unknown array format %q", r.Format)
+	}
+}
+
+// renderElement returns the string an element of a joined slice is rendered to.
+func renderElement(e interface{}) (string, error) {
+	switch val := e.(type) {
+	case string:
+		return val, nil
+	case int:
+		return strconv.Itoa(val), nil
+	case int64:
+		return strconv.FormatInt(val, 10), nil
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(val), nil
+	default:
+		b, err := json.Marshal(val)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+}
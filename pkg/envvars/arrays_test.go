@@ -0,0 +1,109 @@
+package envvars
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderArrays(t *testing.T) {
+	obj := map[string]interface{}{
+		"status": map[string]interface{}{
+			"bootstrapServers": []string{"kafka-0:9092", "kafka-1:9092"},
+			"ports":            []interface{}{int64(9092), 9093.5, true},
+			"listeners": []map[string]interface{}{
+				{"type": "plain", "port": int64(9092)},
+			},
+			"host": "kafka",
+		},
+	}
+
+	type testCase struct {
+		name      string
+		rendering ArrayRendering
+		expected  map[string]string
+	}
+
+	testCases := []testCase{
+		{
+			name: "indexed",
+			expected: map[string]string{
+				"STATUS_BOOTSTRAPSERVERS_0": "kafka-0:9092",
+				"STATUS_BOOTSTRAPSERVERS_1": "kafka-1:9092",
+				"STATUS_PORTS_0":            "9092",
+				"STATUS_PORTS_1":            "9093.5",
+				"STATUS_PORTS_2":            "true",
+				"STATUS_LISTENERS_0_TYPE":   "plain",
+				"STATUS_LISTENERS_0_PORT":   "9092",
+				"STATUS_HOST":               "kafka",
+			},
+		},
+		{
+			name:      "joined with the default separator",
+			rendering: ArrayRendering{Format: ArrayFormatJoin},
+			expected: map[string]string{
+				"STATUS_BOOTSTRAPSERVERS": "kafka-0:9092,kafka-1:9092",
+				"STATUS_PORTS":            "9092,9093.5,true",
+				"STATUS_LISTENERS":        `{"port":9092,"type":"plain"}`,
+				"STATUS_HOST":             "kafka",
+			},
+		},
+		{
+			name:      "joined with a separator",
+			rendering: ArrayRendering{Format: ArrayFormatJoin, Separator: " "},
+			expected: map[string]string{
+				"STATUS_BOOTSTRAPSERVERS": "kafka-0:9092 kafka-1:9092",
+				"STATUS_PORTS":            "9092 9093.5 true",
+				"STATUS_LISTENERS":        `{"port":9092,"type":"plain"}`,
+				"STATUS_HOST":             "kafka",
+			},
+		},
+		{
+			name:      "JSON",
+			rendering: ArrayRendering{Format: ArrayFormatJSON},
+			expected: map[string]string{
+				"STATUS_BOOTSTRAPSERVERS": `["kafka-0:9092","kafka-1:9092"]`,
+				"STATUS_PORTS":            `[9092,9093.5,true]`,
+				"STATUS_LISTENERS":        `[{"port":9092,"type":"plain"}]`,
+				"STATUS_HOST":             "kafka",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rendered, err := RenderArrays(obj, tc.rendering)
+			require.NoError(t, err)
+			actual, err := Build(rendered)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+
+	t.Run("obj is left untouched", func(t *testing.T) {
+		_, err := RenderArrays(obj, ArrayRendering{Format: ArrayFormatJSON})
+		require.NoError(t, err)
+		require.Equal(t, []string{"kafka-0:9092", "kafka-1:9092"}, obj["status"].(map[string]interface{})["bootstrapServers"])
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		_, err := RenderArrays(obj, ArrayRendering{Format: "CSV"})
+		require.EqualError(t, err, `unknown array format "CSV"`)
+	})
+}
+
+func TestParseArrayFormat(t *testing.T) {
+	for s, expected := range map[string]ArrayFormat{
+		"":        ArrayFormatIndexed,
+		"indexed": ArrayFormatIndexed,
+		"Join":    ArrayFormatJoin,
+		"json":    ArrayFormatJSON,
+	} {
+		actual, err := ParseArrayFormat(s)
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	}
+
+	_, err := ParseArrayFormat("csv")
+	require.EqualError(t, err, `unknown array format "csv"`)
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
-	"strings"
 
 	"github.com/imdario/mergo"
 )
@@ -52,58 +51,98 @@ var errUnsupportedType = errors.New("unsupported type")
 // 	"KAFKA_STATUS_LISTENERS_0_ADDRESSES_0_PORT": "9093",
 //
 func Build(obj interface{}, path ...string) (map[string]string, error) {
+	return BuildWithNaming(obj, DefaultNaming, path...)
+}
+
+// BuildWithNaming returns an environment variable dictionary with an entry for each leaf
+// containing a scalar value, like Build, naming each entry through the given strategy; the
+// prefixes are handed to the strategy apart from the path of the leaf.
+func BuildWithNaming(obj interface{}, naming NamingStrategy, prefixes ...string) (map[string]string, error) {
+	b := &builder{naming: naming, prefixes: prefixes}
+	return b.build(obj, []string{})
+}
+
+// Leaf is a scalar value of an object, and the path it is found at.
+type Leaf struct {
+	Path  []string
+	Value interface{}
+}
+
+// BuildLeaves returns the same entries as BuildWithNaming, holding the leaves they are named after
+// instead of their values as strings.
+func BuildLeaves(obj interface{}, naming NamingStrategy, prefixes ...string) (map[string]Leaf, error) {
+	b := &builder{naming: naming, prefixes: prefixes, leaves: make(map[string]Leaf)}
+	if _, err := b.build(obj, []string{}); err != nil {
+		return nil, err
+	}
+	return b.leaves, nil
+}
+
+// builder builds environment variable dictionaries, naming the entries through naming.
+type builder struct {
+	naming   NamingStrategy
+	prefixes []string
+	// leaves collects the leaf each entry is named after, when not nil.
+	leaves map[string]Leaf
+}
+
+// build returns the environment variables for the given object, found at path.
+func (b *builder) build(obj interface{}, path []string) (map[string]string, error) {
 	// perform the appropriate action depending on its type; maybe at some point
 	// reflection might be required.
 	switch val := obj.(type) {
 	case map[string]interface{}:
-		return buildMap(val, path)
+		return b.buildMap(val, path)
 	case []map[string]interface{}:
-		return buildSliceOfMap(val, path)
+		return b.buildSliceOfMap(val, path)
 	case string:
-		return buildString(val, path), nil
+		return b.buildString(val, val, path)
 	case int:
-		return buildString(strconv.Itoa(val), path), nil
+		return b.buildString(strconv.Itoa(val), val, path)
 	case int64:
-		return buildString(strconv.FormatInt(val, 10), path), nil
+		return b.buildString(strconv.FormatInt(val, 10), val, path)
 	case float64:
-		return buildString(strconv.FormatFloat(val, 'f', -1, 64), path), nil
+		return b.buildString(strconv.FormatFloat(val, 'f', -1, 64), val, path)
 	case []string:
-		return buildSliceOfStrings(val, path)
+		return b.buildSliceOfStrings(val, path)
 	case []interface{}:
-		return buildSliceOfInterface(val, path)
+		return b.buildSliceOfInterface(val, path)
 	case bool:
-		return buildString(strconv.FormatBool(val), path), nil
+		return b.buildString(strconv.FormatBool(val), val, path)
 	default:
 		return nil, fmt.Errorf("%v: %v", errUnsupportedType, val)
 	}
 }
 
-// buildEnvVarName returns the environment variable name for the given `path`.
-func buildEnvVarName(path []string) string {
-	// remove empty values from path
+// nonEmpty returns the given path without empty values.
+func nonEmpty(path []string) []string {
 	newPath := []string{}
 	for _, p := range path {
 		if len(p) > 0 {
 			newPath = append(newPath, p)
 		}
 	}
-	envVar := strings.Join(newPath, "_")
-	envVar = strings.ReplaceAll(envVar, ".", "_")
-	envVar = strings.ToUpper(envVar)
-	return envVar
+	return newPath
 }
 
 // buildString returns a map containing the environment variable, named using
-// the given `path` and the given `s` value.
-func buildString(val string, path []string) map[string]string {
-	return map[string]string{
-		buildEnvVarName(path): val,
+// the given `path` and the given `s` value; leaf is the value before being converted to a string.
+func (b *builder) buildString(val string, leaf interface{}, path []string) (map[string]string, error) {
+	name, err := b.naming.Name(nonEmpty(b.prefixes), nonEmpty(path))
+	if err != nil {
+		return nil, err
+	}
+	if b.leaves != nil {
+		b.leaves[name] = Leaf{Path: nonEmpty(path), Value: leaf}
 	}
+	return map[string]string{
+		name: val,
+	}, nil
 }
 
 // buildMap returns a map containing environment variables for all the leaves
 // present in the given `obj` map.
-func buildMap(obj map[string]interface{}, path []string) (map[string]string, error) {
+func (b *builder) buildMap(obj map[string]interface{}, path []string) (map[string]string, error) {
 	envVars := make(map[string]string)
 
 	keys := make([]string, 0)
@@ -112,7 +151,7 @@ func buildMap(obj map[string]interface{}, path []string) (map[string]string, err
 	}
 	sort.Strings(keys)
 	for _, k := range keys {
-		if err := buildInner(path, k, obj[k], envVars); err != nil {
+		if err := b.buildInner(path, k, obj[k], envVars); err != nil {
 			return nil, err
 		}
 	}
@@ -121,11 +160,11 @@ func buildMap(obj map[string]interface{}, path []string) (map[string]string, err
 
 // buildSliceOfStrings returns a slice containing environment variables for
 // all the leaves present in the given 'obj' slice of strings.
-func buildSliceOfStrings(obj []string, acc []string) (map[string]string, error) {
+func (b *builder) buildSliceOfStrings(obj []string, acc []string) (map[string]string, error) {
 	envVars := make(map[string]string)
 	for i, v := range obj {
 		k := strconv.Itoa(i)
-		if err := buildInner(acc, k, v, envVars); err != nil {
+		if err := b.buildInner(acc, k, v, envVars); err != nil {
 			return nil, err
 		}
 	}
@@ -134,11 +173,11 @@ func buildSliceOfStrings(obj []string, acc []string) (map[string]string, error)
 
 // buildSliceOfInterface retrurns a slice containing environment variables for
 // all the leaves present in the given 'obj' slice.
-func buildSliceOfInterface(obj []interface{}, acc []string) (map[string]string, error) {
+func (b *builder) buildSliceOfInterface(obj []interface{}, acc []string) (map[string]string, error) {
 	envVars := make(map[string]string)
 	for i, v := range obj {
 		k := strconv.Itoa(i)
-		if err := buildInner(acc, k, v, envVars); err != nil {
+		if err := b.buildInner(acc, k, v, envVars); err != nil {
 			return nil, err
 		}
 	}
@@ -147,11 +186,11 @@ func buildSliceOfInterface(obj []interface{}, acc []string) (map[string]string,
 
 // buildSliceOfMap returns a map containing environment variables for all the
 // leaves present in the given `obj` slice.
-func buildSliceOfMap(obj []map[string]interface{}, acc []string) (map[string]string, error) {
+func (b *builder) buildSliceOfMap(obj []map[string]interface{}, acc []string) (map[string]string, error) {
 	envVars := make(map[string]string)
 	for i, v := range obj {
 		k := strconv.Itoa(i)
-		if err := buildInner(acc, k, v, envVars); err != nil {
+		if err := b.buildInner(acc, k, v, envVars); err != nil {
 			return nil, err
 		}
 	}
@@ -160,13 +199,14 @@ func buildSliceOfMap(obj []map[string]interface{}, acc []string) (map[string]str
 
 // buildInner builds recursively an environment variable map for the given value
 // and merges it with the given `envVars` map.
-func buildInner(
+func (b *builder) buildInner(
 	path []string,
 	key string,
 	value interface{},
 	envVars map[string]string,
 ) error {
-	if envVar, err := Build(value, append(path, key)...); err != nil {
+	innerPath := append(append([]string{}, path...), key)
+	if envVar, err := b.build(value, innerPath); err != nil {
 		return err
 	} else {
 		return mergo.Merge(&envVars, envVar)
@@ -161,3 +161,25 @@ func TestBuild(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildLeaves(t *testing.T) {
+	src := map[string]interface{}{
+		"host": "db.example.com",
+		"conf": map[string]interface{}{
+			"port":  int64(5432),
+			"ratio": 0.5,
+			"tls":   true,
+		},
+		"replicas": []interface{}{"db-0"},
+	}
+
+	actual, err := BuildLeaves(src, DefaultNaming, "", "database")
+	require.NoError(t, err)
+	require.Equal(t, map[string]Leaf{
+		"DATABASE_HOST":       {Path: []string{"host"}, Value: "db.example.com"},
+		"DATABASE_CONF_PORT":  {Path: []string{"conf", "port"}, Value: int64(5432)},
+		"DATABASE_CONF_RATIO": {Path: []string{"conf", "ratio"}, Value: 0.5},
+		"DATABASE_CONF_TLS":   {Path: []string{"conf", "tls"}, Value: true},
+		"DATABASE_REPLICAS_0": {Path: []string{"replicas", "0"}, Value: "db-0"},
+	}, actual)
+}
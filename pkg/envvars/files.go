@@ -0,0 +1,174 @@
+package envvars
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Document holds binding values nested under the prefixes of their sources, as they are before
+// being flattened by Build; e.g. the "host" and "dbCredentials.user" values of a service with the
+// "Database" prefix are held as {"Database": {"host": ..., "dbCredentials": {"user": ...}}}.
+type Document map[string]interface{}
+
+// Add nests a copy of obj under the given prefixes, ignoring empty ones; maps already present at
+// the same location are merged, while other values are replaced.
+func (d Document) Add(obj interface{}, prefixes ...string) error {
+	obj = copyValue(obj)
+	for i := len(prefixes) - 1; i >= 0; i-- {
+		if len(prefixes[i]) > 0 {
+			obj = map[string]interface{}{prefixes[i]: obj}
+		}
+	}
+	m, ok := obj.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%v: %T can't be added without prefixes", errUnsupportedType, obj)
+	}
+	mergeInto(d, m)
+	return nil
+}
+
+// mergeInto merges src into dst, recursing into the maps present in both.
+func mergeInto(dst map[string]interface{}, src map[string]interface{}) {
+	for k, v := range src {
+		srcMap, srcIsMap := v.(map[string]interface{})
+		dstMap, dstIsMap := dst[k].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			mergeInto(dstMap, srcMap)
+		} else {
+			dst[k] = v
+		}
+	}
+}
+
+// copyValue returns a deep copy of the maps and slices in v.
+func copyValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, e := range val {
+			m[k] = copyValue(e)
+		}
+		return m
+	case []map[string]interface{}:
+		s := make([]interface{}, 0, len(val))
+		for _, e := range val {
+			s = append(s, copyValue(e))
+		}
+		return s
+	case []interface{}:
+		s := make([]interface{}, 0, len(val))
+		for _, e := range val {
+			s = append(s, copyValue(e))
+		}
+		return s
+	case []string:
+		return append([]string{}, val...)
+	default:
+		return v
+	}
+}
+
+// PropertiesNaming joins prefixes and path with ".", keeping their case, as it is customary for
+// the keys of Java properties files; e.g. Database.dbCredentials.user.
+var PropertiesNaming NamingStrategy = NamingFunc(func(prefixes []string, path []string) (string, error) {
+	return strings.Join(append(append([]string{}, prefixes...), path...), "."), nil
+})
+
+// FormatEnv returns a .env file assigning the given environment variables, sorted by name; values
+// are double quoted, escaping backslashes, double quotes, dollar signs and new lines. Names that
+// aren't valid environment variable names, as IsValidEnvVarName tells, are left out, since shells
+// and dotenv parsers would reject the whole file.
+func FormatEnv(envVars map[string]string) []byte {
+	var buf bytes.Buffer
+	for _, k := range sortedKeys(envVars) {
+		if !IsValidEnvVarName(k) {
+			continue
+		}
+		v := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "\n", `\n`, "\r", `\r`).Replace(envVars[k])
+		fmt.Fprintf(&buf, "%s=\"%s\"\n", k, v)
+	}
+	return buf.Bytes()
+}
+
+// FormatJSON returns the given document as indented JSON.
+func FormatJSON(doc Document) ([]byte, error) {
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// FormatYAML returns the given document as YAML.
+func FormatYAML(doc Document) ([]byte, error) {
+	return yaml.Marshal(doc)
+}
+
+// FormatProperties returns the given document as a Java properties file, sorted by key and named
+// through PropertiesNaming; keys and values are escaped as java.util.Properties expects, using
+// \uXXXX escapes for characters outside of the printable ASCII range.
+func FormatProperties(doc Document) ([]byte, error) {
+	props, err := BuildWithNaming(map[string]interface{}(doc), PropertiesNaming)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	for _, k := range sortedKeys(props) {
+		fmt.Fprintf(&buf, "%s=%s\n", escapeProperty(k, true), escapeProperty(props[k], false))
+	}
+	return buf.Bytes(), nil
+}
+
+// escapeProperty escapes s to be used as a key, or as a value, of a Java properties file.
+func escapeProperty(s string, key bool) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '=', ':', '#', '!':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case ' ':
+			// spaces are significant anywhere in keys, but only at the beginning of values
+			if key || i == 0 {
+				b.WriteRune('\\')
+			}
+			b.WriteRune(r)
+		default:
+			if r > unicode.MaxASCII || !unicode.IsPrint(r) {
+				for _, u := range utf16.Encode([]rune{r}) {
+					fmt.Fprintf(&b, `\u%04x`, u)
+				}
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	return b.String()
+}
+
+// sortedKeys returns the keys of m, sorted.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,130 @@
+package envvars
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDocument(t *testing.T) Document {
+	svc := map[string]interface{}{
+		"host": "db.example.com",
+		"port": int64(5432),
+		"dbCredentials": map[string]interface{}{
+			"user":     "admin",
+			"password": "p@ss=word: #1",
+		},
+		"replicas": []map[string]interface{}{{"host": "replica-0"}},
+	}
+	doc := Document{}
+	require.NoError(t, doc.Add(svc, "", "Database"))
+	require.NoError(t, doc.Add(map[string]interface{}{"tls": "true"}, "Database"))
+	require.NoError(t, doc.Add(map[string]interface{}{"JDBC_URL": "jdbc:postgresql://db.example.com/app"}))
+
+	// the document holds copies of the added values
+	svc["host"] = "other.example.com"
+	svc["dbCredentials"].(map[string]interface{})["user"] = "other"
+	return doc
+}
+
+func TestDocumentAdd(t *testing.T) {
+	require.Equal(t, Document{
+		"Database": map[string]interface{}{
+			"host": "db.example.com",
+			"port": int64(5432),
+			"tls":  "true",
+			"dbCredentials": map[string]interface{}{
+				"user":     "admin",
+				"password": "p@ss=word: #1",
+			},
+			"replicas": []interface{}{map[string]interface{}{"host": "replica-0"}},
+		},
+		"JDBC_URL": "jdbc:postgresql://db.example.com/app",
+	}, newTestDocument(t))
+
+	require.Error(t, Document{}.Add("value"))
+}
+
+func TestFormatEnv(t *testing.T) {
+	actual := FormatEnv(map[string]string{
+		"DATABASE_HOST":         "db.example.com",
+		"DATABASE_PASSWORD":     `p"a$s\w`,
+		"DATABASE_CA":           "line 1\nline 2",
+		"dbCredentials/user":    "admin",
+		"spring.datasource.url": "jdbc:postgresql://db",
+		"0_PORT":                "5432",
+	})
+	require.Equal(t, `DATABASE_CA="line 1\nline 2"
+DATABASE_HOST="db.example.com"
+DATABASE_PASSWORD="p\"a\$s\\w"
+`, string(actual))
+}
+
+func TestFormatJSON(t *testing.T) {
+	actual, err := FormatJSON(newTestDocument(t))
+	require.NoError(t, err)
+	require.Equal(t, `{
+  "Database": {
+    "dbCredentials": {
+      "password": "p@ss=word: #1",
+      "user": "admin"
+    },
+    "host": "db.example.com",
+    "port": 5432,
+    "replicas": [
+      {
+        "host": "replica-0"
+      }
+    ],
+    "tls": "true"
+  },
+  "JDBC_URL": "jdbc:postgresql://db.example.com/app"
+}
+`, string(actual))
+}
+
+func TestFormatYAML(t *testing.T) {
+	actual, err := FormatYAML(newTestDocument(t))
+	require.NoError(t, err)
+	require.Equal(t, `Database:
+  dbCredentials:
+    password: 'p@ss=word: #1'
+    user: admin
+  host: db.example.com
+  port: 5432
+  replicas:
+  - host: replica-0
+  tls: "true"
+JDBC_URL: jdbc:postgresql://db.example.com/app
+`, string(actual))
+}
+
+func TestFormatProperties(t *testing.T) {
+	actual, err := FormatProperties(newTestDocument(t))
+	require.NoError(t, err)
+	require.Equal(t, `Database.dbCredentials.password=p@ss\=word\: \#1
+Database.dbCredentials.user=admin
+Database.host=db.example.com
+Database.port=5432
+Database.replicas.0.host=replica-0
+Database.tls=true
+JDBC_URL=jdbc\:postgresql\://db.example.com/app
+`, string(actual))
+}
+
+func TestEscapeProperty(t *testing.T) {
+	testCases := []struct {
+		s        string
+		key      bool
+		expected string
+	}{
+		{s: "a key", key: true, expected: `a\ key`},
+		{s: " a value ", expected: `\ a value `},
+		{s: "line\\1\nline\t2", expected: `line\\1\nline\t2`},
+		{s: "café", expected: `caf\u00e9`},
+		{s: "🔑", expected: `\ud83d\udd11`},
+	}
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, escapeProperty(tc.s, tc.key), tc.s)
+	}
+}
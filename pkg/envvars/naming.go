@@ -0,0 +1,158 @@
+package envvars
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// NamingStrategy names the environment variable holding a leaf value.
+type NamingStrategy interface {
+	// Name returns the name of the variable holding the value found at path in the object being
+	// built, given the prefixes informed to BuildWithNaming; empty prefixes and path components
+	// have been removed.
+	Name(prefixes []string, path []string) (string, error)
+}
+
+// NamingFunc is a function implementing NamingStrategy.
+type NamingFunc func(prefixes []string, path []string) (string, error)
+
+// Name calls f(prefixes, path).
+func (f NamingFunc) Name(prefixes []string, path []string) (string, error) {
+	return f(prefixes, path)
+}
+
+const (
+	// DefaultNamingStrategy is the name of DefaultNaming.
+	DefaultNamingStrategy = "Default"
+	// NoPrefixNamingStrategy is the name of NoPrefixNaming.
+	NoPrefixNamingStrategy = "NoPrefix"
+	// PreserveCaseNamingStrategy is the name of PreserveCaseNaming.
+	PreserveCaseNamingStrategy = "PreserveCase"
+	// FlatNamingStrategy is the name of FlatNaming.
+	FlatNamingStrategy = "Flat"
+)
+
+var (
+	// DefaultNaming joins prefixes and path with "_", replacing "." with "_" and turning the result
+	// to upper case; e.g. DATABASE_STATUS_DBCREDENTIALS_USER for the prefix "Database" and the path
+	// "status", "dbCredentials", "user".
+	DefaultNaming NamingStrategy = NamingFunc(func(prefixes []string, path []string) (string, error) {
+		return strings.ToUpper(joinName(prefixes, path)), nil
+	})
+
+	// NoPrefixNaming is DefaultNaming ignoring the prefixes; e.g. STATUS_DBCREDENTIALS_USER.
+	NoPrefixNaming NamingStrategy = NamingFunc(func(prefixes []string, path []string) (string, error) {
+		return strings.ToUpper(joinName(nil, path)), nil
+	})
+
+	// PreserveCaseNaming is DefaultNaming keeping the case of prefixes and path; e.g.
+	// Database_status_dbCredentials_user.
+	PreserveCaseNaming NamingStrategy = NamingFunc(func(prefixes []string, path []string) (string, error) {
+		return joinName(prefixes, path), nil
+	})
+
+	// FlatNaming names variables after the binding keys, as the Service Binding specification
+	// mandates for the entries of a binding Secret: prefixes are ignored, path components are
+	// joined with "_" keeping their case, and characters other than letters, digits, "-", "." and
+	// "_" are replaced with "_"; e.g. dbCredentials_user, or ca.crt.
+	FlatNaming NamingStrategy = NamingFunc(func(prefixes []string, path []string) (string, error) {
+		return invalidFlatNameChars.ReplaceAllString(strings.Join(path, "_"), "_"), nil
+	})
+
+	// FilePathNaming names values after their location, as files are laid out by spec-compliant
+	// client libraries: prefixes are ignored and path components are joined with "/" keeping their
+	// case, nested maps becoming directories; e.g. dbCredentials/user. See SanitizeFilePath for
+	// the Secret key holding each file.
+	FilePathNaming NamingStrategy = NamingFunc(func(prefixes []string, path []string) (string, error) {
+		return strings.Join(path, "/"), nil
+	})
+)
+
+var invalidFlatNameChars = regexp.MustCompile(`[^-._a-zA-Z0-9]`)
+
+// joinName joins prefixes and path with "_", replacing "." with "_".
+func joinName(prefixes []string, path []string) string {
+	parts := append(append([]string{}, prefixes...), path...)
+	return strings.ReplaceAll(strings.Join(parts, "_"), ".", "_")
+}
+
+// NamingData is the data available to naming templates.
+type NamingData struct {
+	// Prefix holds the prefixes joined with "_".
+	Prefix string
+	// Prefixes holds the prefixes.
+	Prefixes []string
+	// Key holds the path components joined with "_".
+	Key string
+	// Path holds the path components.
+	Path []string
+}
+
+var namingFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"join":  func(sep string, parts []string) string { return strings.Join(parts, sep) },
+	"replace": func(old string, new string, s string) string {
+		return strings.ReplaceAll(s, old, new)
+	},
+}
+
+// templateNaming names variables executing a Go template with NamingData.
+type templateNaming struct {
+	tmpl *template.Template
+}
+
+var _ NamingStrategy = (*templateNaming)(nil)
+
+// NewTemplateNaming returns a NamingStrategy executing the given Go template with NamingData, e.g.
+// "{{.Prefix}}_{{.Key}}" or "{{upper .Key}}"; the upper, lower, join and replace functions are
+// available. Leading and trailing "_" are removed from the result, so that templates render as
+// expected when there are no prefixes.
+func NewTemplateNaming(text string) (NamingStrategy, error) {
+	tmpl, err := template.New("naming").Funcs(namingFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid naming template %q: %v", text, err)
+	}
+	return &templateNaming{tmpl: tmpl}, nil
+}
+
+func (n *templateNaming) Name(prefixes []string, path []string) (string, error) {
+	var buf bytes.Buffer
+	data := NamingData{
+		Prefix:   strings.Join(prefixes, "_"),
+		Prefixes: prefixes,
+		Key:      strings.Join(path, "_"),
+		Path:     path,
+	}
+	if err := n.tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("naming template failed for %q: %v", data.Key, err)
+	}
+	name := strings.Trim(buf.String(), "_")
+	if len(name) == 0 {
+		return "", fmt.Errorf("naming template produced an empty name for %q", data.Key)
+	}
+	return name, nil
+}
+
+// ParseNamingStrategy returns the strategy with the given name, either DefaultNamingStrategy,
+// NoPrefixNamingStrategy, PreserveCaseNamingStrategy or FlatNamingStrategy, or a template
+// strategy when s contains "{{"; an empty value stands for DefaultNaming.
+func ParseNamingStrategy(s string) (NamingStrategy, error) {
+	switch s {
+	case "", DefaultNamingStrategy:
+		return DefaultNaming, nil
+	case NoPrefixNamingStrategy:
+		return NoPrefixNaming, nil
+	case PreserveCaseNamingStrategy:
+		return PreserveCaseNaming, nil
+	case FlatNamingStrategy:
+		return FlatNaming, nil
+	}
+	if strings.Contains(s, "{{") {
+		return NewTemplateNaming(s)
+	}
+	return nil, fmt.Errorf("unknown naming strategy %q", s)
+}
@@ -0,0 +1,156 @@
+package envvars
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamingStrategies(t *testing.T) {
+	type testCase struct {
+		name     string
+		strategy string
+		prefixes []string
+		path     []string
+		expected string
+	}
+
+	testCases := []testCase{
+		{
+			name:     "default",
+			strategy: DefaultNamingStrategy,
+			prefixes: []string{"binding", "Database"},
+			path:     []string{"status", "dbCredentials", "user"},
+			expected: "BINDING_DATABASE_STATUS_DBCREDENTIALS_USER",
+		},
+		{
+			name:     "default replaces dots",
+			strategy: "",
+			path:     []string{"tls", "ca.crt"},
+			expected: "TLS_CA_CRT",
+		},
+		{
+			name:     "no prefix",
+			strategy: NoPrefixNamingStrategy,
+			prefixes: []string{"binding", "Database"},
+			path:     []string{"status", "dbCredentials", "user"},
+			expected: "STATUS_DBCREDENTIALS_USER",
+		},
+		{
+			name:     "preserve case",
+			strategy: PreserveCaseNamingStrategy,
+			prefixes: []string{"binding", "Database"},
+			path:     []string{"status", "dbCredentials", "user"},
+			expected: "binding_Database_status_dbCredentials_user",
+		},
+		{
+			name:     "flat",
+			strategy: FlatNamingStrategy,
+			prefixes: []string{"binding", "Database"},
+			path:     []string{"dbCredentials", "user"},
+			expected: "dbCredentials_user",
+		},
+		{
+			name:     "flat keeps dots and replaces invalid characters",
+			strategy: FlatNamingStrategy,
+			path:     []string{"tls", "ca.crt", "a/b c"},
+			expected: "tls_ca.crt_a_b_c",
+		},
+		{
+			name:     "template",
+			strategy: "{{.Prefix}}_{{.Key}}",
+			prefixes: []string{"binding", "Database"},
+			path:     []string{"host"},
+			expected: "binding_Database_host",
+		},
+		{
+			name:     "template without prefix",
+			strategy: "{{.Prefix}}_{{.Key}}",
+			path:     []string{"host"},
+			expected: "host",
+		},
+		{
+			name:     "template with functions",
+			strategy: `{{upper .Prefix}}__{{join "." .Path | replace "." "-" | lower}}`,
+			prefixes: []string{"db"},
+			path:     []string{"TLS", "ca"},
+			expected: "DB__tls-ca",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			naming, err := ParseNamingStrategy(tc.strategy)
+			require.NoError(t, err)
+			actual, err := naming.Name(tc.prefixes, tc.path)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestParseNamingStrategyErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		strategy string
+	}{
+		{name: "unknown strategy", strategy: "CamelCase"},
+		{name: "invalid template", strategy: "{{.Prefix"},
+		{name: "unknown function", strategy: "{{title .Key}}"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseNamingStrategy(tc.strategy)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestTemplateNamingErrors(t *testing.T) {
+	t.Run("unknown field", func(t *testing.T) {
+		naming, err := NewTemplateNaming("{{.Name}}")
+		require.NoError(t, err)
+		_, err = naming.Name(nil, []string{"host"})
+		require.Error(t, err)
+	})
+
+	t.Run("empty name", func(t *testing.T) {
+		naming, err := NewTemplateNaming("{{.Prefix}}_")
+		require.NoError(t, err)
+		_, err = naming.Name(nil, []string{"host"})
+		require.EqualError(t, err, `naming template produced an empty name for "host"`)
+	})
+}
+
+func TestBuildWithNaming(t *testing.T) {
+	src := map[string]interface{}{
+		"host": "db.example.com",
+		"dbCredentials": map[string]interface{}{
+			"user": "admin",
+		},
+		"ports": []interface{}{int64(5432), int64(5433)},
+		"":      "ignored component",
+	}
+
+	t.Run("template", func(t *testing.T) {
+		naming, err := NewTemplateNaming("{{.Prefix}}_{{.Key}}")
+		require.NoError(t, err)
+		actual, err := BuildWithNaming(src, naming, "", "db")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"db_host":               "db.example.com",
+			"db_dbCredentials_user": "admin",
+			"db_ports_0":            "5432",
+			"db_ports_1":            "5433",
+			"db":                    "ignored component",
+		}, actual)
+	})
+
+	t.Run("naming errors are returned", func(t *testing.T) {
+		naming, err := NewTemplateNaming("{{.Prefix}}")
+		require.NoError(t, err)
+		_, err = BuildWithNaming(src, naming)
+		require.Error(t, err)
+	})
+}
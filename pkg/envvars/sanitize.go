@@ -0,0 +1,94 @@
+package envvars
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// MaxKeyLength is the maximum length of a Secret key, and so of a binding key.
+const MaxKeyLength = 253
+
+var (
+	// errEmptyKey is returned when sanitizing an empty key.
+	errEmptyKey = errors.New("empty key")
+	// errKeyTooLong is returned when sanitizing a key longer than MaxKeyLength.
+	errKeyTooLong = errors.New("key longer than 253 characters")
+
+	invalidEnvVarNameChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+	invalidSecretKeyChars  = regexp.MustCompile(`[^-._a-zA-Z0-9]`)
+	validEnvVarName        = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// IsValidEnvVarName returns true when name can be read by POSIX shells, i.e. it is made of ASCII
+// letters, digits and "_", not starting with a digit, and is a valid Secret key.
+func IsValidEnvVarName(name string) bool {
+	return validEnvVarName.MatchString(name) && len(name) <= MaxKeyLength
+}
+
+// IsValidSecretKey returns true when key is accepted by Kubernetes as a Secret key, i.e. it is
+// made of ASCII letters, digits, "-", "_" and ".", isn't "." nor "..", and is at most MaxKeyLength
+// long.
+func IsValidSecretKey(key string) bool {
+	return len(key) > 0 && len(key) <= MaxKeyLength && key != "." && key != ".." &&
+		!invalidSecretKeyChars.MatchString(key)
+}
+
+// SanitizeEnvVarName returns a valid environment variable name for name: characters other than
+// ASCII letters, digits and "_" are replaced with "_", and "_" is prepended to names starting
+// with a digit; e.g. "db-host" becomes "db_host" and "0_HOST" becomes "_0_HOST". Valid names are
+// returned as they are, while an error is returned for empty names and names that are longer than
+// MaxKeyLength once sanitized, which can't be used.
+func SanitizeEnvVarName(name string) (string, error) {
+	if len(name) == 0 {
+		return "", errEmptyKey
+	}
+	name = invalidEnvVarNameChars.ReplaceAllString(name, "_")
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	if len(name) > MaxKeyLength {
+		return "", errKeyTooLong
+	}
+	return name, nil
+}
+
+// SanitizeSecretKey returns a valid Secret key for key: characters other than ASCII letters,
+// digits, "-", "_" and "." are replaced with "_", and the "." and ".." keys become "_" and "__";
+// e.g. "tls/ca.crt" becomes "tls_ca.crt". Valid keys are returned as they are, while an error is
+// returned for empty keys and keys longer than MaxKeyLength, which can't be used.
+func SanitizeSecretKey(key string) (string, error) {
+	if len(key) == 0 {
+		return "", errEmptyKey
+	}
+	if len(key) > MaxKeyLength {
+		return "", errKeyTooLong
+	}
+	switch key {
+	case ".":
+		return "_", nil
+	case "..":
+		return "__", nil
+	}
+	return invalidSecretKeyChars.ReplaceAllString(key, "_"), nil
+}
+
+// SanitizeFilePath returns a valid file path for p, a "/" separated path such as the ones named by
+// FilePathNaming, sanitizing each of its components as SanitizeSecretKey does, together with the
+// Secret key holding the file, made of the components joined with "."; e.g. "tls/ca.crt" is held
+// by the "tls.ca.crt" key. An error is returned for paths with empty components and paths whose
+// key is longer than MaxKeyLength.
+func SanitizeFilePath(p string) (string, string, error) {
+	components := strings.Split(p, "/")
+	for i, c := range components {
+		var err error
+		if components[i], err = SanitizeSecretKey(c); err != nil {
+			return "", "", err
+		}
+	}
+	key := strings.Join(components, ".")
+	if len(key) > MaxKeyLength {
+		return "", "", errKeyTooLong
+	}
+	return strings.Join(components, "/"), key, nil
+}
@@ -0,0 +1,117 @@
+package envvars
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizeEnvVarName(t *testing.T) {
+	testCases := []struct {
+		name        string
+		expected    string
+		expectedErr string
+	}{
+		{name: "DATABASE_HOST", expected: "DATABASE_HOST"},
+		{name: "_private", expected: "_private"},
+		{name: "MY-DB_HOST", expected: "MY_DB_HOST"},
+		{name: "tls/ca.crt", expected: "tls_ca_crt"},
+		{name: "0_HOST", expected: "_0_HOST"},
+		{name: "HÔTE", expected: "H_TE"},
+		{name: "", expectedErr: "empty key"},
+		{name: strings.Repeat("A", MaxKeyLength), expected: strings.Repeat("A", MaxKeyLength)},
+		{name: "0" + strings.Repeat("A", MaxKeyLength-1), expectedErr: "key longer than 253 characters"},
+	}
+	for _, tc := range testCases {
+		actual, err := SanitizeEnvVarName(tc.name)
+		if len(tc.expectedErr) > 0 {
+			require.EqualError(t, err, tc.expectedErr, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.expected, actual, tc.name)
+		require.True(t, IsValidEnvVarName(actual), actual)
+	}
+}
+
+func TestSanitizeSecretKey(t *testing.T) {
+	testCases := []struct {
+		key         string
+		expected    string
+		expectedErr string
+	}{
+		{key: "ca.crt", expected: "ca.crt"},
+		{key: "my-db_host", expected: "my-db_host"},
+		{key: "0", expected: "0"},
+		{key: "tls/ca.crt", expected: "tls_ca.crt"},
+		{key: "user name", expected: "user_name"},
+		{key: ".", expected: "_"},
+		{key: "..", expected: "__"},
+		{key: "", expectedErr: "empty key"},
+		{key: strings.Repeat("a", MaxKeyLength+1), expectedErr: "key longer than 253 characters"},
+	}
+	for _, tc := range testCases {
+		actual, err := SanitizeSecretKey(tc.key)
+		if len(tc.expectedErr) > 0 {
+			require.EqualError(t, err, tc.expectedErr, tc.key)
+			continue
+		}
+		require.NoError(t, err, tc.key)
+		require.Equal(t, tc.expected, actual, tc.key)
+		require.True(t, IsValidSecretKey(actual), actual)
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	require.False(t, IsValidEnvVarName("0_HOST"))
+	require.False(t, IsValidEnvVarName("ca.crt"))
+	require.False(t, IsValidEnvVarName(""))
+	require.True(t, IsValidSecretKey("ca.crt"))
+	require.False(t, IsValidSecretKey(".."))
+	require.False(t, IsValidSecretKey("a/b"))
+}
+
+func TestSanitizeFilePath(t *testing.T) {
+	testCases := []struct {
+		path         string
+		expectedPath string
+		expectedKey  string
+		expectedErr  string
+	}{
+		{path: "host", expectedPath: "host", expectedKey: "host"},
+		{path: "dbCredentials/user", expectedPath: "dbCredentials/user", expectedKey: "dbCredentials.user"},
+		{path: "tls/ca.crt", expectedPath: "tls/ca.crt", expectedKey: "tls.ca.crt"},
+		{path: "../etc/passwd", expectedPath: "__/etc/passwd", expectedKey: "__.etc.passwd"},
+		{path: "user name/first", expectedPath: "user_name/first", expectedKey: "user_name.first"},
+		{path: "tls//ca.crt", expectedErr: "empty key"},
+		{path: strings.Repeat("a/", MaxKeyLength/2+1) + "a", expectedErr: "key longer than 253 characters"},
+	}
+	for _, tc := range testCases {
+		path, key, err := SanitizeFilePath(tc.path)
+		if len(tc.expectedErr) > 0 {
+			require.EqualError(t, err, tc.expectedErr, tc.path)
+			continue
+		}
+		require.NoError(t, err, tc.path)
+		require.Equal(t, tc.expectedPath, path, tc.path)
+		require.Equal(t, tc.expectedKey, key, tc.path)
+		require.True(t, IsValidSecretKey(key), key)
+	}
+}
+
+func TestFilePathNaming(t *testing.T) {
+	actual, err := BuildWithNaming(map[string]interface{}{
+		"host": "db.example.com",
+		"dbCredentials": map[string]interface{}{
+			"user": "admin",
+		},
+		"hosts": []string{"db-0"},
+	}, FilePathNaming, "Database")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"host":               "db.example.com",
+		"dbCredentials/user": "admin",
+		"hosts/0":            "db-0",
+	}, actual)
+}
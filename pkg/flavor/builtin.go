@@ -0,0 +1,83 @@
+package flavor
+
+// Flavors supported by the operator out of the box.
+const (
+	// SpringBoot translates relational databases to the spring.datasource.* properties, MongoDB
+	// to spring.data.mongodb.uri and Redis to the spring.redis.* properties.
+	SpringBoot = "SpringBoot"
+	// Quarkus translates relational databases to the quarkus.datasource.* properties, MongoDB to
+	// quarkus.mongodb.connection-string and Redis to quarkus.redis.hosts.
+	Quarkus = "Quarkus"
+	// Node translates relational databases to DATABASE_URL, MongoDB to MONGODB_URI and Redis to
+	// REDIS_URL, as most Node.js libraries expect.
+	Node = "Node"
+)
+
+// setIfNotEmpty sets k to v in m, unless v is empty.
+func setIfNotEmpty(m map[string]string, k string, v string) {
+	if len(v) > 0 {
+		m[k] = v
+	}
+}
+
+func translateSpringBoot(svc Service) (map[string]string, error) {
+	d, err := newDatasource(svc)
+	if d == nil || err != nil {
+		return nil, err
+	}
+	entries := make(map[string]string)
+	switch {
+	case d.isRelational():
+		setIfNotEmpty(entries, "spring.datasource.url", d.jdbcURL())
+		setIfNotEmpty(entries, "spring.datasource.username", d.username)
+		setIfNotEmpty(entries, "spring.datasource.password", d.password)
+	case d.kind == MongoDB:
+		setIfNotEmpty(entries, "spring.data.mongodb.uri", d.connectionURL())
+	case d.kind == Redis:
+		if len(d.host) == 0 {
+			setIfNotEmpty(entries, "spring.redis.url", d.url)
+			break
+		}
+		entries["spring.redis.host"] = d.host
+		entries["spring.redis.port"] = d.port
+		setIfNotEmpty(entries, "spring.redis.password", d.password)
+	}
+	return entries, nil
+}
+
+func translateQuarkus(svc Service) (map[string]string, error) {
+	d, err := newDatasource(svc)
+	if d == nil || err != nil {
+		return nil, err
+	}
+	entries := make(map[string]string)
+	switch {
+	case d.isRelational():
+		entries["quarkus.datasource.db-kind"] = quarkusDBKinds[d.kind]
+		setIfNotEmpty(entries, "quarkus.datasource.jdbc.url", d.jdbcURL())
+		setIfNotEmpty(entries, "quarkus.datasource.username", d.username)
+		setIfNotEmpty(entries, "quarkus.datasource.password", d.password)
+	case d.kind == MongoDB:
+		setIfNotEmpty(entries, "quarkus.mongodb.connection-string", d.connectionURL())
+	case d.kind == Redis:
+		setIfNotEmpty(entries, "quarkus.redis.hosts", d.connectionURL())
+	}
+	return entries, nil
+}
+
+func translateNode(svc Service) (map[string]string, error) {
+	d, err := newDatasource(svc)
+	if d == nil || err != nil {
+		return nil, err
+	}
+	entries := make(map[string]string)
+	switch {
+	case d.isRelational():
+		setIfNotEmpty(entries, "DATABASE_URL", d.connectionURL())
+	case d.kind == MongoDB:
+		setIfNotEmpty(entries, "MONGODB_URI", d.connectionURL())
+	case d.kind == Redis:
+		setIfNotEmpty(entries, "REDIS_URL", d.connectionURL())
+	}
+	return entries, nil
+}
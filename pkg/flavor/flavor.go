@@ -0,0 +1,190 @@
+// Package flavor translates the binding values collected from services into the conventions of
+// application frameworks, e.g. the spring.datasource.* properties of Spring Boot.
+package flavor
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Translator translates the binding values of a service into the entries expected by a framework.
+type Translator interface {
+	// Translate returns the entries for the given service, named as framework properties, e.g.
+	// spring.datasource.url, or environment variables; nil is returned when the framework doesn't
+	// support the kind of service.
+	Translate(svc Service) (map[string]string, error)
+}
+
+// TranslatorFunc is a function implementing Translator.
+type TranslatorFunc func(svc Service) (map[string]string, error)
+
+// Translate calls f(svc).
+func (f TranslatorFunc) Translate(svc Service) (map[string]string, error) {
+	return f(svc)
+}
+
+// ErrUnknownFlavor is returned when no Translator has been registered for a flavor.
+type ErrUnknownFlavor string
+
+func (e ErrUnknownFlavor) Error() string {
+	return fmt.Sprintf("unknown binding flavor %q", string(e))
+}
+
+func IsErrUnknownFlavor(err error) bool {
+	_, ok := err.(ErrUnknownFlavor)
+	return ok
+}
+
+// Registry holds the Translator of each flavor; it is safe for concurrent use.
+type Registry struct {
+	mu          sync.RWMutex
+	translators map[string]Translator
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{translators: make(map[string]Translator)}
+}
+
+// NewBuiltinRegistry returns a registry containing the flavors supported by the operator out of
+// the box.
+func NewBuiltinRegistry() *Registry {
+	r := NewRegistry()
+	r.Register(SpringBoot, TranslatorFunc(translateSpringBoot))
+	r.Register(Quarkus, TranslatorFunc(translateQuarkus))
+	r.Register(Node, TranslatorFunc(translateNode))
+	return r
+}
+
+// DefaultRegistry is the registry consulted by the operator.
+var DefaultRegistry = NewBuiltinRegistry()
+
+// Register registers the translator of the given flavor, replacing any previous one.
+func (r *Registry) Register(name string, t Translator) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.translators[name] = t
+}
+
+// Lookup returns the translator of the given flavor, or ErrUnknownFlavor.
+func (r *Registry) Lookup(name string) (Translator, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.translators[name]
+	if !ok {
+		return nil, ErrUnknownFlavor(name)
+	}
+	return t, nil
+}
+
+// Names returns the registered flavors, sorted.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.translators))
+	for n := range r.translators {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Service holds the binding values collected from a service, as seen by translators.
+type Service struct {
+	fields map[string]string
+}
+
+// NewService returns the service holding the given binding values, as collected before being
+// flattened into environment variables. Scalar values are looked up by the last component of
+// their path, ignoring case, "-" and "_"; values closer to the root take precedence, so that
+// {"host": ..., "replicas": [{"host": ...}]} is seen as the first host.
+func NewService(values map[string]interface{}) Service {
+	s := Service{fields: make(map[string]string)}
+	level := []interface{}{values}
+	for len(level) > 0 {
+		var next []interface{}
+		for _, v := range level {
+			next = append(next, s.addLevel(v)...)
+		}
+		level = next
+	}
+	return s
+}
+
+// addLevel adds the scalar values found in v, returning the nested maps and slices.
+func (s Service) addLevel(v interface{}) []interface{} {
+	var nested []interface{}
+	add := func(k string, e interface{}) {
+		switch val := e.(type) {
+		case string:
+			s.setField(k, val)
+		case int:
+			s.setField(k, strconv.Itoa(val))
+		case int64:
+			s.setField(k, strconv.FormatInt(val, 10))
+		case float64:
+			s.setField(k, strconv.FormatFloat(val, 'f', -1, 64))
+		case bool:
+			s.setField(k, strconv.FormatBool(val))
+		case nil:
+		default:
+			nested = append(nested, e)
+		}
+	}
+	switch val := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			add(k, val[k])
+		}
+	case []interface{}:
+		for _, e := range val {
+			nested = append(nested, e)
+		}
+	case []map[string]interface{}:
+		for _, e := range val {
+			nested = append(nested, e)
+		}
+	}
+	return nested
+}
+
+func (s Service) setField(name string, value string) {
+	name = normalizeFieldName(name)
+	if _, ok := s.fields[name]; !ok && len(value) > 0 {
+		s.fields[name] = value
+	}
+}
+
+var fieldNameSeparators = regexp.MustCompile(`[-_.]`)
+
+func normalizeFieldName(name string) string {
+	return strings.ToLower(fieldNameSeparators.ReplaceAllString(name, ""))
+}
+
+// Get returns the value of the first of the given fields the service has, or an empty string.
+func (s Service) Get(names ...string) string {
+	for _, n := range names {
+		if v, ok := s.fields[normalizeFieldName(n)]; ok {
+			return v
+		}
+	}
+	return ""
+}
+
+var invalidEnvVarChars = regexp.MustCompile(`[^A-Z0-9_]`)
+
+// EnvVarName returns the environment variable frameworks map to the given property, replacing
+// characters other than letters and digits with "_" and turning the result to upper case, e.g.
+// SPRING_DATASOURCE_URL for spring.datasource.url, as both Spring Boot and Quarkus do.
+func EnvVarName(property string) string {
+	return invalidEnvVarChars.ReplaceAllString(strings.ToUpper(property), "_")
+}
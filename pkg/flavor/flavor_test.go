@@ -0,0 +1,118 @@
+package flavor
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// render returns the entries of each flavor for svc, one "key=value" line per entry.
+func render(t *testing.T, svc Service) []byte {
+	var buf bytes.Buffer
+	for _, name := range DefaultRegistry.Names() {
+		tr, err := DefaultRegistry.Lookup(name)
+		require.NoError(t, err)
+		entries, err := tr.Translate(svc)
+		require.NoError(t, err)
+		fmt.Fprintf(&buf, "# %s\n", name)
+		keys := make([]string, 0, len(entries))
+		for k := range entries {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(&buf, "%s=%s\n", k, entries[k])
+		}
+	}
+	return buf.Bytes()
+}
+
+// TestTranslatorsGolden translates the services in testdata/*.yaml, comparing the entries of every
+// flavor with testdata/*.golden; run with -update to regenerate those.
+func TestTranslatorsGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "*.yaml"))
+	require.NoError(t, err)
+	require.NotEmpty(t, inputs)
+
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), ".yaml")
+		t.Run(name, func(t *testing.T) {
+			data, err := ioutil.ReadFile(input)
+			require.NoError(t, err)
+			values := make(map[string]interface{})
+			require.NoError(t, yaml.Unmarshal(data, &values))
+
+			actual := render(t, NewService(values))
+			golden := filepath.Join("testdata", name+".golden")
+			if *update {
+				require.NoError(t, ioutil.WriteFile(golden, actual, 0644))
+			}
+			expected, err := ioutil.ReadFile(golden)
+			require.NoError(t, err)
+			require.Equal(t, string(expected), string(actual))
+		})
+	}
+}
+
+func TestTranslatorErrors(t *testing.T) {
+	svc := NewService(map[string]interface{}{"type": "postgresql", "username": "admin"})
+	for _, name := range DefaultRegistry.Names() {
+		tr, err := DefaultRegistry.Lookup(name)
+		require.NoError(t, err)
+		_, err = tr.Translate(svc)
+		require.EqualError(t, err, "postgresql service has neither host nor url", name)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewBuiltinRegistry()
+	require.Equal(t, []string{Node, Quarkus, SpringBoot}, r.Names())
+
+	_, err := r.Lookup("Rails")
+	require.True(t, IsErrUnknownFlavor(err))
+	require.EqualError(t, err, `unknown binding flavor "Rails"`)
+
+	r.Register("Rails", TranslatorFunc(func(svc Service) (map[string]string, error) {
+		return map[string]string{"DATABASE_HOST": svc.Get("host")}, nil
+	}))
+	tr, err := r.Lookup("Rails")
+	require.NoError(t, err)
+	entries, err := tr.Translate(NewService(map[string]interface{}{"host": "db"}))
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"DATABASE_HOST": "db"}, entries)
+}
+
+func TestNewService(t *testing.T) {
+	svc := NewService(map[string]interface{}{
+		"replicas": []map[string]interface{}{{"host": "replica-0"}},
+		"primary": map[string]interface{}{
+			"Host":    "primary",
+			"DB_NAME": "orders",
+		},
+		"port":    int64(5432),
+		"tls":     true,
+		"empty":   "",
+		"missing": nil,
+	})
+	require.Equal(t, "primary", svc.Get("host"))
+	require.Equal(t, "orders", svc.Get("database", "dbName"))
+	require.Equal(t, "5432", svc.Get("port"))
+	require.Equal(t, "true", svc.Get("tls"))
+	require.Equal(t, "", svc.Get("empty", "missing", "unknown"))
+}
+
+func TestEnvVarName(t *testing.T) {
+	require.Equal(t, "SPRING_DATASOURCE_URL", EnvVarName("spring.datasource.url"))
+	require.Equal(t, "QUARKUS_DATASOURCE_DB_KIND", EnvVarName("quarkus.datasource.db-kind"))
+	require.Equal(t, "DATABASE_URL", EnvVarName("DATABASE_URL"))
+}
@@ -0,0 +1,547 @@
+// Package templatefuncs provides the functions available to the Go templates of binding mappings.
+//
+// Functions follow the names, and the order of the arguments, of the Sprig library
+// (http://masterminds.github.io/sprig/), taking the piped value last, e.g. {{ .password | b64dec }}
+// or {{ .port | default 5432 }}. Only deterministic functions without side effects are provided,
+// so that the same resources always render the same binding values: functions returning the
+// current time, random values or reading the environment of the operator are left out.
+package templatefuncs
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+const (
+	// maxCount is the largest count repeat and the indentation functions accept.
+	maxCount = 10000
+	// maxOutputLength is the largest string repeat and the indentation functions build, that of
+	// the largest Secret.
+	maxOutputLength = 1 << 20
+)
+
+// FuncMap returns the functions available to mapping templates.
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		// strings
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"title":      title,
+		"trim":       strings.TrimSpace,
+		"trimAll":    func(cutset string, s string) string { return strings.Trim(s, cutset) },
+		"trimPrefix": func(prefix string, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix string, s string) string { return strings.TrimSuffix(s, suffix) },
+		"replace":    func(old string, new string, s string) string { return strings.ReplaceAll(s, old, new) },
+		"contains":   func(substr string, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix":  func(prefix string, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix string, s string) bool { return strings.HasSuffix(s, suffix) },
+		"repeat":     repeat,
+		"substr":     substr,
+		"trunc":      trunc,
+		"indent":     indent,
+		"nindent":    nindent,
+		"quote":      func(v interface{}) string { return strconv.Quote(toString(v)) },
+		"squote":     func(v interface{}) string { return "'" + toString(v) + "'" },
+		"splitList":  func(sep string, s string) []string { return strings.Split(s, sep) },
+		"join":       join,
+		"toString":   toString,
+		"atoi":       atoi,
+		"toInt":      toInt,
+
+		// defaults
+		"default":  defaultValue,
+		"empty":    empty,
+		"coalesce": coalesce,
+		"ternary":  ternary,
+		"required": required,
+
+		// encoding
+		"b64enc":       func(v interface{}) string { return base64.StdEncoding.EncodeToString([]byte(toString(v))) },
+		"b64dec":       b64dec,
+		"json":         toJSON,
+		"toJson":       toJSON,
+		"toPrettyJson": toPrettyJSON,
+		"fromJson":     fromJSON,
+		"sha256sum":    sha256sum,
+
+		// urls
+		"urlEscape":     func(v interface{}) string { return url.QueryEscape(toString(v)) },
+		"urlPathEscape": func(v interface{}) string { return url.PathEscape(toString(v)) },
+		"urlUserinfo":   urlUserinfo,
+		"urlParse":      urlParse,
+
+		// regular expressions
+		"regexMatch":      regexMatch,
+		"regexFind":       regexFind,
+		"regexFindAll":    regexFindAll,
+		"regexReplaceAll": regexReplaceAll,
+		"regexSplit":      regexSplit,
+
+		// dicts and lists
+		"dict":      dict,
+		"get":       get,
+		"hasKey":    hasKey,
+		"keys":      keys,
+		"pick":      pick,
+		"omit":      omit,
+		"list":      func(elements ...interface{}) []interface{} { return elements },
+		"first":     first,
+		"last":      last,
+		"rest":      rest,
+		"has":       has,
+		"sortAlpha": sortAlpha,
+	}
+}
+
+// toString returns the string representation of v; nil is rendered as an empty string.
+func toString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case []byte:
+		return string(val)
+	case int:
+		return strconv.Itoa(val)
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	case error:
+		return val.Error()
+	case fmt.Stringer:
+		return val.String()
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// toInt converts numbers and numeric strings to int.
+func toInt(v interface{}) (int, error) {
+	switch val := v.(type) {
+	case int:
+		return val, nil
+	case int32:
+		return int(val), nil
+	case int64:
+		return int(val), nil
+	case float64:
+		return int(val), nil
+	case string:
+		return atoi(val)
+	default:
+		return 0, fmt.Errorf("can't convert %T to int", v)
+	}
+}
+
+func atoi(s string) (int, error) {
+	i, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("%q is not an integer", s)
+	}
+	return i, nil
+}
+
+// toList converts slices of any type to []interface{}.
+func toList(v interface{}) ([]interface{}, error) {
+	if l, ok := v.([]interface{}); ok {
+		return l, nil
+	}
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+		return nil, fmt.Errorf("%T is not a list", v)
+	}
+	l := make([]interface{}, val.Len())
+	for i := range l {
+		l[i] = val.Index(i).Interface()
+	}
+	return l, nil
+}
+
+// title upper cases the first letter of each word of s.
+func title(s string) string {
+	runes := []rune(s)
+	for i, r := range runes {
+		if i == 0 || unicode.IsSpace(runes[i-1]) {
+			runes[i] = unicode.ToTitle(r)
+		}
+	}
+	return string(runes)
+}
+
+// substr returns the runes of s from start to end, clamped to its length; a negative end stands
+// for the end of s.
+func substr(start int, end int, s string) string {
+	runes := []rune(s)
+	if end < 0 || end > len(runes) {
+		end = len(runes)
+	}
+	if start < 0 {
+		start = 0
+	}
+	if start > end {
+		return ""
+	}
+	return string(runes[start:end])
+}
+
+// trunc returns the first n runes of s, or the last -n runes when n is negative.
+func trunc(n int, s string) string {
+	runes := []rune(s)
+	if n < 0 {
+		if -n >= len(runes) {
+			return s
+		}
+		return string(runes[len(runes)+n:])
+	}
+	if n >= len(runes) {
+		return s
+	}
+	return string(runes[:n])
+}
+
+// checkCount fails when the given count of a function is negative or larger than maxCount, or
+// when the string it would build is longer than maxOutputLength.
+func checkCount(name string, count int, length int) error {
+	if count < 0 {
+		return fmt.Errorf("%s: negative count %d", name, count)
+	}
+	if count > maxCount {
+		return fmt.Errorf("%s: count %d is larger than %d", name, count, maxCount)
+	}
+	if length > maxOutputLength {
+		return fmt.Errorf("%s: result is longer than %d bytes", name, maxOutputLength)
+	}
+	return nil
+}
+
+// repeat returns count copies of s.
+func repeat(count int, s string) (string, error) {
+	if err := checkCount("repeat", count, count*len(s)); err != nil {
+		return "", err
+	}
+	return strings.Repeat(s, count), nil
+}
+
+// indent prepends the given number of spaces to each line of s.
+func indent(spaces int, s string) (string, error) {
+	if err := checkCount("indent", spaces, len(s)+spaces*(strings.Count(s, "\n")+1)); err != nil {
+		return "", err
+	}
+	pad := strings.Repeat(" ", spaces)
+	return pad + strings.ReplaceAll(s, "\n", "\n"+pad), nil
+}
+
+// nindent prepends a new line to s, indented as indent does.
+func nindent(spaces int, s string) (string, error) {
+	if err := checkCount("nindent", spaces, 1+len(s)+spaces*(strings.Count(s, "\n")+1)); err != nil {
+		return "", err
+	}
+	pad := strings.Repeat(" ", spaces)
+	return "\n" + pad + strings.ReplaceAll(s, "\n", "\n"+pad), nil
+}
+
+// join joins the string representation of the elements of list with sep.
+func join(sep string, list interface{}) (string, error) {
+	l, err := toList(list)
+	if err != nil {
+		return "", err
+	}
+	parts := make([]string, 0, len(l))
+	for _, e := range l {
+		parts = append(parts, toString(e))
+	}
+	return strings.Join(parts, sep), nil
+}
+
+// empty returns true for nil, false, zero numbers and empty strings, lists and maps.
+func empty(v interface{}) bool {
+	val := reflect.ValueOf(v)
+	switch val.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return val.Len() == 0
+	case reflect.Bool:
+		return !val.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return val.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return val.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return val.Float() == 0
+	case reflect.Ptr, reflect.Interface:
+		return val.IsNil()
+	}
+	return false
+}
+
+// defaultValue returns given, or d when given is empty; given is optional so that missing values,
+// which templates don't pass along, can be defaulted, e.g. {{ .status.port | default 5432 }}.
+func defaultValue(d interface{}, given ...interface{}) interface{} {
+	if len(given) == 0 || empty(given[0]) {
+		return d
+	}
+	return given[0]
+}
+
+// coalesce returns the first non empty value, or nil.
+func coalesce(values ...interface{}) interface{} {
+	for _, v := range values {
+		if !empty(v) {
+			return v
+		}
+	}
+	return nil
+}
+
+// ternary returns t when cond is true, and f otherwise.
+func ternary(t interface{}, f interface{}, cond bool) interface{} {
+	if cond {
+		return t
+	}
+	return f
+}
+
+// required returns v, failing the template with msg when v is empty.
+func required(msg string, v interface{}) (interface{}, error) {
+	if empty(v) {
+		return nil, errors.New(msg)
+	}
+	return v, nil
+}
+
+func b64dec(v interface{}) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(toString(v))
+	if err != nil {
+		return "", fmt.Errorf("invalid base64 value: %v", err)
+	}
+	return string(b), nil
+}
+
+func toJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func toPrettyJSON(v interface{}) (string, error) {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func fromJSON(s string) (interface{}, error) {
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return nil, fmt.Errorf("invalid JSON value: %v", err)
+	}
+	return v, nil
+}
+
+func sha256sum(v interface{}) string {
+	sum := sha256.Sum256([]byte(toString(v)))
+	return hex.EncodeToString(sum[:])
+}
+
+// urlUserinfo returns the userinfo of a URL made of the given user and password, escaped as URLs
+// require, e.g. "admin:p%40ss" for the "p@ss" password; the password is left out when empty.
+func urlUserinfo(user interface{}, password interface{}) string {
+	if p := toString(password); len(p) > 0 {
+		return url.UserPassword(toString(user), p).String()
+	}
+	return url.User(toString(user)).String()
+}
+
+// urlParse returns the components of the given URL: scheme, userinfo, username, password, host,
+// hostname, port, path, query and fragment.
+func urlParse(s string) (map[string]interface{}, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	password, _ := u.User.Password()
+	userinfo := ""
+	if u.User != nil {
+		userinfo = u.User.String()
+	}
+	return map[string]interface{}{
+		"scheme":   u.Scheme,
+		"userinfo": userinfo,
+		"username": u.User.Username(),
+		"password": password,
+		"host":     u.Host,
+		"hostname": u.Hostname(),
+		"port":     u.Port(),
+		"path":     u.Path,
+		"query":    u.RawQuery,
+		"fragment": u.Fragment,
+	}, nil
+}
+
+func regexMatch(regex string, s string) (bool, error) {
+	return regexp.MatchString(regex, s)
+}
+
+func regexFind(regex string, s string) (string, error) {
+	re, err := regexp.Compile(regex)
+	if err != nil {
+		return "", err
+	}
+	return re.FindString(s), nil
+}
+
+func regexFindAll(regex string, s string, n int) ([]string, error) {
+	re, err := regexp.Compile(regex)
+	if err != nil {
+		return nil, err
+	}
+	return re.FindAllString(s, n), nil
+}
+
+func regexReplaceAll(regex string, s string, repl string) (string, error) {
+	re, err := regexp.Compile(regex)
+	if err != nil {
+		return "", err
+	}
+	return re.ReplaceAllString(s, repl), nil
+}
+
+func regexSplit(regex string, s string, n int) ([]string, error) {
+	re, err := regexp.Compile(regex)
+	if err != nil {
+		return nil, err
+	}
+	return re.Split(s, n), nil
+}
+
+// dict returns a map made of the given key and value pairs.
+func dict(pairs ...interface{}) (map[string]interface{}, error) {
+	if len(pairs)%2 != 0 {
+		return nil, errors.New("dict requires key and value pairs")
+	}
+	d := make(map[string]interface{}, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		d[toString(pairs[i])] = pairs[i+1]
+	}
+	return d, nil
+}
+
+func get(d map[string]interface{}, key string) interface{} {
+	return d[key]
+}
+
+func hasKey(d map[string]interface{}, key string) bool {
+	_, ok := d[key]
+	return ok
+}
+
+// keys returns the sorted keys of the given maps.
+func keys(dicts ...map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	ks := []string{}
+	for _, d := range dicts {
+		for k := range d {
+			if !seen[k] {
+				seen[k] = true
+				ks = append(ks, k)
+			}
+		}
+	}
+	sort.Strings(ks)
+	return ks
+}
+
+// pick returns a copy of d holding only the given keys.
+func pick(d map[string]interface{}, ks ...string) map[string]interface{} {
+	picked := make(map[string]interface{})
+	for _, k := range ks {
+		if v, ok := d[k]; ok {
+			picked[k] = v
+		}
+	}
+	return picked
+}
+
+// omit returns a copy of d without the given keys.
+func omit(d map[string]interface{}, ks ...string) map[string]interface{} {
+	omitted := make(map[string]interface{}, len(d))
+	for k, v := range d {
+		omitted[k] = v
+	}
+	for _, k := range ks {
+		delete(omitted, k)
+	}
+	return omitted
+}
+
+func first(list interface{}) (interface{}, error) {
+	l, err := toList(list)
+	if err != nil || len(l) == 0 {
+		return nil, err
+	}
+	return l[0], nil
+}
+
+func last(list interface{}) (interface{}, error) {
+	l, err := toList(list)
+	if err != nil || len(l) == 0 {
+		return nil, err
+	}
+	return l[len(l)-1], nil
+}
+
+func rest(list interface{}) ([]interface{}, error) {
+	l, err := toList(list)
+	if err != nil || len(l) == 0 {
+		return nil, err
+	}
+	return l[1:], nil
+}
+
+// has returns true when list holds needle.
+func has(needle interface{}, list interface{}) (bool, error) {
+	l, err := toList(list)
+	if err != nil {
+		return false, err
+	}
+	for _, e := range l {
+		if reflect.DeepEqual(e, needle) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// sortAlpha returns the string representation of the elements of list, sorted.
+func sortAlpha(list interface{}) ([]string, error) {
+	l, err := toList(list)
+	if err != nil {
+		return nil, err
+	}
+	sorted := make([]string, 0, len(l))
+	for _, e := range l {
+		sorted = append(sorted, toString(e))
+	}
+	sort.Strings(sorted)
+	return sorted, nil
+}
@@ -23,5 +23,9 @@ func BuildTestRESTMapper() meta.RESTMapper {
 		schema.GroupVersionKind{Kind: "Deployment", Version: "v1", Group: "apps"},
 		meta.RESTScopeNamespace,
 	)
+	restMapper.Add(
+		schema.GroupVersionKind{Kind: "Database", Version: "v1alpha1", Group: "postgresql.baiju.dev"},
+		meta.RESTScopeNamespace,
+	)
 	return restMapper
 }
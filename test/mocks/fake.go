@@ -6,7 +6,7 @@ import (
 	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
-	apiextensionv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apiextensionv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -166,10 +166,10 @@ func (f *Fake) AddMockedUnstructuredKnativeService(name string, matchLabels map[
 }
 
 func (f *Fake) AddMockedUnstructuredDatabaseCRD() *unstructured.Unstructured {
-	require.NoError(f.t, apiextensionv1beta1.AddToScheme(f.S))
+	require.NoError(f.t, apiextensionv1.AddToScheme(f.S))
 	c, err := UnstructuredDatabaseCRDMock(f.ns)
 	require.NoError(f.t, err)
-	f.S.AddKnownTypes(apiextensionv1beta1.SchemeGroupVersion, &apiextensionv1beta1.CustomResourceDefinition{})
+	f.S.AddKnownTypes(apiextensionv1.SchemeGroupVersion, &apiextensionv1.CustomResourceDefinition{})
 	f.objs = append(f.objs, c)
 	return c
 }
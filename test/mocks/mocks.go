@@ -7,7 +7,7 @@ import (
 	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	apiextensionv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apiextensionv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	ustrv1 "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -88,7 +88,7 @@ var (
 	}
 )
 
-func DatabaseCRDMock(ns string) apiextensionv1beta1.CustomResourceDefinition {
+func DatabaseCRDMock(ns string) apiextensionv1.CustomResourceDefinition {
 	CRDPlural := "databases"
 	FullCRDName := CRDPlural + "." + CRDName
 	annotations := map[string]string{
@@ -96,21 +96,27 @@ func DatabaseCRDMock(ns string) apiextensionv1beta1.CustomResourceDefinition {
 		"service.binding/password": "path={.status.dbCredentials},objectType=Secret,valueKey=password",
 	}
 
-	crd := apiextensionv1beta1.CustomResourceDefinition{
+	crd := apiextensionv1.CustomResourceDefinition{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "CustomResourceDefinition",
-			APIVersion: "apiextensions.k8s.io/v1beta1",
+			APIVersion: "apiextensions.k8s.io/v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			//Namespace:   ns,
 			Name:        FullCRDName,
 			Annotations: annotations,
 		},
-		Spec: apiextensionv1beta1.CustomResourceDefinitionSpec{
-			Group:   CRDName,
-			Version: CRDVersion,
-			Scope:   apiextensionv1beta1.NamespaceScoped,
-			Names: apiextensionv1beta1.CustomResourceDefinitionNames{
+		Spec: apiextensionv1.CustomResourceDefinitionSpec{
+			Group: CRDName,
+			Versions: []apiextensionv1.CustomResourceDefinitionVersion{
+				{
+					Name:    CRDVersion,
+					Served:  true,
+					Storage: true,
+				},
+			},
+			Scope: apiextensionv1.NamespaceScoped,
+			Names: apiextensionv1.CustomResourceDefinitionNames{
 				Plural: CRDPlural,
 				Kind:   CRDKind,
 			},